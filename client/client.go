@@ -3,43 +3,39 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
-	"github.com/hpe-hcss/lh-cdc-singularity/restclient"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"strings"
-	"time"
+	"net/http"
 )
 
 const AUTH_SVC_INFO string = "/info/authsvcinfo"
 
 type ClientInterface interface {
-	CreateVolume(volume *model.Volume) (*model.Volume, error)
-	DeleteVolume(volumeId string) (*model.Volume, error)
-	GetVolume(volumeId string) (*model.Volume, error)
-	ListVolumes() (*[]model.Volume, error)
-	CreateVolumeAttachment(attachment *model.VolumeAttachment) (*model.VolumeAttachment, error)
-	DeleteVolumeAttachment(attachmentId string) (*model.VolumeAttachment, error)
-	GetVolumeAttachment(attachmentId string) (*model.VolumeAttachment, error)
-	ListVolumeAttachments() (*[]model.VolumeAttachment, error)
-	ListVolumeFlavors() (*[]model.VolumeFlavor, error)
-	Login() error
-	Logout()
+	CreateVolume(ctx context.Context, volume *model.Volume) (*model.Volume, error)
+	DeleteVolume(ctx context.Context, volumeId string) (*model.Volume, error)
+	GetVolume(ctx context.Context, volumeId string) (*model.Volume, error)
+	ListVolumes(ctx context.Context) (*[]model.Volume, error)
+	UpdateVolume(ctx context.Context, volume *model.Volume) (*model.Volume, error)
+	CreateVolumeAttachment(ctx context.Context, attachment *model.VolumeAttachment) (*model.VolumeAttachment, error)
+	DeleteVolumeAttachment(ctx context.Context, attachmentId string) (*model.VolumeAttachment, error)
+	GetVolumeAttachment(ctx context.Context, attachmentId string) (*model.VolumeAttachment, error)
+	ListVolumeAttachments(ctx context.Context) (*[]model.VolumeAttachment, error)
+	ListVolumeFlavors(ctx context.Context) (*[]model.VolumeFlavor, error)
+	WaitForVolumeState(ctx context.Context, volumeId string, state glmClient.VolumeState) (*model.Volume, error)
+	SetWaitOptions(opts model.WaitOptions)
+	Login(ctx context.Context) error
+	Logout(ctx context.Context) error
+	GetSession() (*model.Session, error)
+	GetSessionToken(ctx context.Context) (string, error)
 	GetGlmCredentials() (map[string]string, error)
-}
-
-var UndefinedResponseError = errors.New("undefined response type")
-var TokenExpiredError = errors.New("Token is expired")
-var UndefinedResponseMsg = "undefined response type"
-
-type errorMsg struct {
-	Message string
+	GetTransport() *glm.Transport
 }
 
 type Client struct {
@@ -48,45 +44,67 @@ type Client struct {
 	Password     string
 	SessionToken string
 	MembershipID string
+	Transport    *glm.Transport
+	WaitOptions  model.WaitOptions
+	apiClient    *glmClient.APIClient
 }
 
-func NewClient(URL, username, password, membershipID string) ClientInterface {
-	return &Client{
-		URL,
-		username,
-		password,
-		"",
-		membershipID,
-	}
+// NewClient creates a client for the GLM portal at URL. All requests of the
+// client are sent with httpClient.
+func NewClient(URL, username, password, membershipID string, httpClient *http.Client) ClientInterface {
+	cli := &Client{
+		Url:          URL,
+		UserName:     username,
+		Password:     password,
+		MembershipID: membershipID,
+		WaitOptions:  model.DefaultWaitOptions(),
+	}
+	cli.Transport = glm.NewTransport(URL, membershipID, httpClient, cli.GetSessionToken)
+	return cli
 }
 
-func (cli *Client) Login() error {
-	log.Infof("Login function")
-	client := restclient.NewRestClient()
-	userInfo := restclient.UserInfo{
-		UserName: cli.UserName,
-		UserPwd:  cli.Password,
+// NewClientFromConfig creates a client for the GLM portal and membership
+// configured in the [glm_credentials] section of plugin.conf.
+func NewClientFromConfig(glmCredDetails map[string]string, username, password string) (ClientInterface, error) {
+	tlsConfig, err := glm.NewTLSConfig(glmCredDetails)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
 	}
-	header := map[string]string{}
-
-	// Get authorization service information
-	statusCode, responseBody, err := client.ExecuteRestRequest("GET", AUTH_SVC_INFO, "",
-		"", userInfo, cli.Url, "", 0, nil, header, "")
+	retryPolicy, err := glm.NewRetryPolicy(glmCredDetails)
 	if err != nil {
-		msg := fmt.Sprintf("ExecuteRestRequest error: %+v", err)
-		log.Errorf(msg)
-		return errors.New(msg)
+		log.Errorln(err)
+		return nil, err
+	}
+	proxy, err := glm.NewProxyFunc(glmCredDetails)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
 	}
-	log.Infof("responseBody %+v", responseBody)
-	if statusCode != restclient.StatusCodeOk {
-		return status.Errorf(codes.Internal, fmt.Sprintf("failed to get auth service "+
-			"info, error: %v", statusCode))
+	headers, err := glm.NewExtraHeaders(glmCredDetails)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
 	}
+	tracer, err := glm.NewTracer(glmCredDetails)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	httpClient := glm.NewHTTPClient(tlsConfig, retryPolicy, proxy, headers, tracer)
+	return NewClient(glmCredDetails[constants.GLM_PORTAL], username, password,
+		glmCredDetails[constants.MEMBERSHIP_ID], httpClient), nil
+}
 
-	// parse the authsvcinfo
+func (cli *Client) Login(ctx context.Context) error {
+	log.Infof("Login function")
+	// Get authorization service information
 	var info model.AuthSvcInfo
-	if err := json.Unmarshal(responseBody, &info); err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("failed to parse auth service info, error: %v", statusCode))
+	err := cli.Transport.Do(ctx, &glm.Request{Operation: "get auth service info", Method: http.MethodGet,
+		Path: AUTH_SVC_INFO, Anonymous: true}, &info)
+	if err != nil {
+		log.Errorln(err)
+		return err
 	}
 
 	log.Infof("AuthSvcInfo is %+v", info)
@@ -101,29 +119,80 @@ func (cli *Client) Login() error {
 	authReq.Password = cli.Password
 	authReq.Realm = constants.PASSWORD_REALM
 	authReq.GrantType = constants.GRANT_PASSWORD_REALM
-	authReq.Scope = constants.OPEN_ID
+	// offline_access asks for a refresh token, used to renew the session
+	// before it expires
+	authReq.Scope = constants.OPEN_ID + " " + constants.OFFLINE_ACCESS
 	authReq.Audience = authAudience
 
-	body, _ := json.Marshal(&authReq)
+	resp, err := cli.requestToken(ctx, authUrl, &authReq)
+	if err != nil {
+		return err
+	}
+	return cli.saveSession(resp, authUrl, authClientId, "")
+}
 
-	if statusCode, responseBody, err = client.ExecuteRestRequest("POST", "/oauth/token", "",
-		"", userInfo, authUrl, "", 0, body, header, ""); err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("failed to get OAUTH token, error: %v", err))
+// RefreshSession renews a cached session that is about to expire. It uses the
+// refresh token when the auth service issued one and otherwise logs in again
+// with the password-realm flow.
+func (cli *Client) RefreshSession(ctx context.Context, cached *model.CachedSession) (*model.CachedSession, error) {
+	log.Infof("RefreshSession function")
+	if cached.RefreshToken != "" && cached.AuthURL != "" {
+		refreshReq := model.RefreshRequest{
+			GrantType:    constants.GRANT_REFRESH_TOKEN,
+			ClientID:     cached.AuthClientID,
+			RefreshToken: cached.RefreshToken,
+		}
+		resp, err := cli.requestToken(ctx, cached.AuthURL, &refreshReq)
+		if err == nil {
+			err = cli.saveSession(resp, cached.AuthURL, cached.AuthClientID, cached.RefreshToken)
+		}
+		if err == nil {
+			log.Infof("GLM session refreshed with the refresh token")
+			return model.NewSessionCache().Get(cli.sessionKey())
+		}
+		log.Warnf("refreshing the GLM session with the refresh token failed: %v", err)
 	}
-	if statusCode != restclient.StatusCodeOk {
-		return status.Errorf(codes.Internal, fmt.Sprintf("failed to get OAUTH token, error: %v", statusCode))
+	if cli.UserName == "" || cli.Password == "" {
+		return nil, &model.APIError{Category: model.ErrorUnauthorized,
+			Message: "GLM session is about to expire and no password is available to renew it"}
 	}
+	if err := cli.Login(ctx); err != nil {
+		return nil, err
+	}
+	return model.NewSessionCache().Get(cli.sessionKey())
+}
 
-	// parse JWT
+// requestToken posts authReq to the token endpoint of the auth service.
+func (cli *Client) requestToken(ctx context.Context, authURL string, authReq interface{}) (*model.AuthResponse, error) {
 	var resp model.AuthResponse
-	if err := json.Unmarshal(responseBody, &resp); err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("failed to parse OAUTH token, error: %v", err))
+	err := cli.Transport.Do(ctx, &glm.Request{Operation: "get OAUTH token", Method: http.MethodPost,
+		BaseURL: authURL, Path: "/oauth/token", Body: authReq, Anonymous: true}, &resp)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
 	}
-	log.Infof("resp is %+v", resp)
+	log.Infof("OAUTH token of type %s received", resp.TokenType)
+	return &resp, nil
+}
+
+// saveSession stores the tokens of resp in the per-user session cache. The
+// auth service does not always rotate refresh tokens, in which case
+// refreshToken is kept.
+func (cli *Client) saveSession(resp *model.AuthResponse, authURL, authClientID, refreshToken string) error {
 	cli.SessionToken = resp.IDToken
-	// save session token in the conf file.
-	glm := &model.GLMCredDetails{}
-	err = glm.UpdateGLMCredentials(constants.SESSION_TOKEN, resp.IDToken)
+	if resp.RefreshToken != "" {
+		refreshToken = resp.RefreshToken
+	}
+	err := model.NewSessionCache().Put(model.CachedSession{
+		Portal:       cli.Url,
+		Username:     cli.UserName,
+		MembershipID: cli.MembershipID,
+		SessionToken: resp.IDToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    model.TokenExpiry(resp.IDToken, resp.ExpiresIn),
+		AuthURL:      authURL,
+		AuthClientID: authClientID,
+	})
 	if err != nil {
 		msg := fmt.Sprintf("error: %+v", err)
 		log.Errorf(msg)
@@ -132,117 +201,97 @@ func (cli *Client) Login() error {
 	return nil
 }
 
-func (cli *Client) Logout() {
-	//Need to write the definition
-	log.Infof("Logout")
+// Logout ends the GLM session by removing the cached session token. The auth
+// service cannot revoke ID tokens, so a copy of the token taken before logout
+// stays valid until it expires. Without a username every session cached for
+// the portal and membership is removed.
+func (cli *Client) Logout(ctx context.Context) error {
+	log.Infof("Logout function")
+	if _, err := model.NewSessionCache().Delete(cli.sessionKey()); err != nil {
+		msg := fmt.Sprintf("error: %+v", err)
+		log.Errorf(msg)
+		return errors.New(msg)
+	}
+	cli.SessionToken = ""
+	return nil
 }
 
-func (cli *Client) CreateVolume(vol *model.Volume) (*model.Volume, error) {
+// GetSession describes the cached GLM session. It returns model.TokenError
+// when the user is not logged in.
+func (cli *Client) GetSession() (*model.Session, error) {
+	cached, err := model.NewSessionCache().Get(cli.sessionKey())
+	if err != nil {
+		return nil, err
+	}
+	session := model.NewSession(cached.SessionToken)
+	session.Portal = cached.Portal
+	session.MembershipID = cached.MembershipID
+	if session.Username == "" {
+		session.Username = cached.Username
+	}
+	return session, nil
+}
+
+func (cli *Client) CreateVolume(ctx context.Context, vol *model.Volume) (*model.Volume, error) {
 	log.Infof("Create volume req %v\n", vol)
-	var errMsg errorMsg
 	volume := glmClient.NewVolume{}
 	volume.Name = vol.Name
-	volume.Description = vol.Description
+	volume.Description = model.EncodeDescription(vol.Description, vol.Labels)
 	volume.FlavorID = vol.FlavorID
 	volume.Capacity = vol.Capacity
 	volume.LocationID = vol.LocationID
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumesApi.Add(ctx, volume)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("create volume", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumesApi.Add(ctx, volume)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("create volume error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("Create volume error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
 	log.Infof("Create volume response %v", result)
-	var resp glmClient.Volume
-	state := result.State
-	for iteration := constants.MIN_RETRY_COUNT; iteration < constants.MAX_RETRY_COUNT; iteration++ {
-		if state != "allocated" {
-			time.Sleep(constants.SLEEP_TIME * time.Second)
-			resp, _, err = r.VolumesApi.GetByID(ctx, result.ID)
-			if err != nil {
-				_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-				msg := fmt.Sprintf("get volume failed with error: %+v\n", errMsg.Message)
-				log.Errorf(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			break
+	resp := result
+	if cli.WaitOptions.Wait && result.State != glmClient.VOLUMESTATE_ALLOCATED {
+		resp, err = cli.waitForVolume(ctx, r, result.ID, glmClient.VOLUMESTATE_ALLOCATED)
+		if err != nil {
+			log.Errorln(err)
+			return nil, err
 		}
-		state = resp.State
-	}
-	if state != "allocated" {
-		msg := fmt.Sprintf("Create volume failed with volume state : %v", state)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
 	}
 	volumeResp := model.CreateResponse(resp, constants.CREATE)
 	//covert create volume results into model.Volume and return it
 	return volumeResp, nil
 }
 
-func (cli *Client) DeleteVolume(volumeID string) (*model.Volume, error) {
+func (cli *Client) DeleteVolume(ctx context.Context, volumeID string) (*model.Volume, error) {
 	log.Infof("DeleteVolume volume id: %s", volumeID)
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	httpResponse, err := r.VolumesApi.Delete(ctx, volumeID)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("delete volume", httpResponse, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	httpResponse, err := r.VolumesApi.Delete(ctx, volumeID)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("delete volume error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("Delete volume failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
 
-	log.Infof("Delete volume response %v", httpResponse)
+	log.Infof("Delete volume response %v", redact.Value(httpResponse))
 	if httpResponse.StatusCode != constants.STATUS_OK {
-		msg := fmt.Sprintf("Delete volume failed with status code = %d", httpResponse.StatusCode)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
+		err = glm.StatusError("delete volume", httpResponse, nil)
+		log.Errorln(err)
+		return nil, err
 	}
 	var resp glmClient.Volume
 	resp.ID = volumeID
 	resp.State = constants.VOLUME_STATE_DELETING
-	state := resp.State
-	for iteration := constants.MIN_RETRY_COUNT; iteration < constants.MAX_RETRY_COUNT; iteration++ {
-		if state != constants.STATE_DELETED {
-			time.Sleep(constants.SLEEP_TIME * time.Second)
-			resp, _, err = r.VolumesApi.GetByID(ctx, resp.ID)
-			if err != nil {
-				_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-				msg := fmt.Sprintf("Get volume failed with error: %+v", errMsg.Message)
-				log.Errorf(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			break
+	if cli.WaitOptions.Wait {
+		resp, err = cli.waitForVolume(ctx, r, volumeID, glmClient.VOLUMESTATE_DELETED)
+		if err != nil {
+			log.Errorln(err)
+			return nil, err
 		}
-		state = resp.State
-	}
-	if state != constants.STATE_DELETED {
-		msg := fmt.Sprintf("Delete volume failed with volume state : %v", state)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
 	}
 	volumeResp := model.CreateResponse(resp, constants.DELETE)
 	log.Infof("Delete volume response structure %+v", volumeResp)
@@ -250,27 +299,18 @@ func (cli *Client) DeleteVolume(volumeID string) (*model.Volume, error) {
 	return volumeResp, nil
 }
 
-func (cli *Client) GetVolume(volumeID string) (*model.Volume, error) {
+func (cli *Client) GetVolume(ctx context.Context, volumeID string) (*model.Volume, error) {
 	log.Infof("GetVolume volume id: %s", volumeID)
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumesApi.GetByID(ctx, volumeID)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("get volume", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumesApi.GetByID(ctx, volumeID)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("get volume error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("Get volume failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
 	log.Infof("Get volume response %v", result)
 	volumeResp := model.CreateResponse(result, constants.GET)
 	log.Infof("Get volume response structure %+v", volumeResp)
@@ -278,27 +318,18 @@ func (cli *Client) GetVolume(volumeID string) (*model.Volume, error) {
 	return volumeResp, nil
 }
 
-func (cli *Client) ListVolumes() (*[]model.Volume, error) {
+func (cli *Client) ListVolumes(ctx context.Context) (*[]model.Volume, error) {
 	log.Infof("List Volume")
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumesApi.List(ctx)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("list volumes", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumesApi.List(ctx)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("list volume error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("List volumes failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, err
-	}
 	log.Infof("List volume response %v", result)
 	volumesList := []model.Volume{}
 	for _, item := range result {
@@ -308,56 +339,92 @@ func (cli *Client) ListVolumes() (*[]model.Volume, error) {
 	return &volumesList, nil
 }
 
-func (cli *Client) CreateVolumeAttachment(
+// UpdateVolume changes the capacity, description and labels of a volume. A
+// capacity or description left empty and nil labels are not changed, and the
+// capacity can only grow. The volume returned carries the capacity it had
+// before the update.
+func (cli *Client) UpdateVolume(ctx context.Context, vol *model.Volume) (*model.Volume, error) {
+	log.Infof("UpdateVolume req %v", vol)
+	r := cli.GetREST()
+	current, httpResp, err := r.VolumesApi.GetByID(ctx, vol.VolumeID)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		err = glm.APIClientError("get volume", httpResp, err)
+		log.Errorln(err)
+		return nil, err
+	}
+	if vol.Capacity != 0 && vol.Capacity < current.Capacity {
+		err = model.ValidationError(fmt.Errorf("capacity of volume %s can only grow, it is %d",
+			vol.VolumeID, current.Capacity))
+		log.Errorln(err)
+		return nil, err
+	}
+	// the volume read carries the ETag GLM checks the update against
+	update := current
+	if vol.Capacity != 0 {
+		update.Capacity = vol.Capacity
+	}
+	description, labels := model.DecodeDescription(current.Description)
+	if vol.Description != "" {
+		description = vol.Description
+	}
+	if vol.Labels != nil {
+		labels = vol.Labels
+	}
+	update.Description = model.EncodeDescription(description, labels)
+	result, httpResp, err := r.VolumesApi.Update(ctx, vol.VolumeID, update)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		err = glm.APIClientError("update volume", httpResp, err)
+		log.Errorln(err)
+		return nil, err
+	}
+	log.Infof("Update volume response %v", result)
+	resp := result
+	if cli.WaitOptions.Wait && result.State != glmClient.VOLUMESTATE_ALLOCATED &&
+		result.State != glmClient.VOLUMESTATE_VISIBLE {
+		resp, err = cli.waitForVolume(ctx, r, result.ID, glmClient.VOLUMESTATE_ALLOCATED)
+		if err != nil {
+			log.Errorln(err)
+			return nil, err
+		}
+	}
+	volumeResp := model.CreateResponse(resp, constants.UPDATE)
+	volumeResp.PreviousCapacity = current.Capacity
+	return volumeResp, nil
+}
+
+func (cli *Client) CreateVolumeAttachment(ctx context.Context,
 	volumeAttachment *model.VolumeAttachment) (*model.VolumeAttachment, error) {
 	log.Infof("create volume attachment: %v", volumeAttachment)
-	var errMsg errorMsg
 	volAttachment := glmClient.NewVolumeAttachment{}
 	volAttachment.Name = volumeAttachment.Name
 	volAttachment.VolumeID = volumeAttachment.VolumeID
 	protocol := glmClient.ProtocolParameters{}
 	protocol.Protocol = constants.PROTOCOL_FUSE
 	volAttachment.Protocol = protocol
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumeAttachmentsApi.Add(ctx, volAttachment)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("create volume attachment", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Debugf("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumeAttachmentsApi.Add(ctx, volAttachment)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("create volume attachment failed with error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("attach volumes failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-	log.Infof("Create volume attachment response %v", result)
-	var resp glmClient.VolumeAttachment
-	state := result.State
-	for iteration := constants.MIN_RETRY_COUNT; iteration < constants.MAX_RETRY_COUNT; iteration++ {
-		if state != constants.VOLUME_ATTACHMENT_STATE_READY {
-			time.Sleep(constants.SLEEP_TIME * time.Second)
-			resp, _, err = r.VolumeAttachmentsApi.GetByID(ctx, result.ID)
-			if err != nil {
-				_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-				msg := fmt.Sprintf("Get volume attachment failed with error: %+v", errMsg.Message)
-				log.Errorf(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			break
+	log.Infof("Create volume attachment response %v", redact.Value(result))
+	resp := result
+	if cli.WaitOptions.Wait && result.State != glmClient.VASTATEENUM_READY {
+		resp, err = cli.waitForVolumeAttachment(ctx, r, result.ID, glmClient.VASTATEENUM_READY)
+		if err != nil {
+			log.Errorln(err)
+			return nil, err
 		}
-		state = resp.State
-	}
-	if state != constants.VOLUME_ATTACHMENT_STATE_READY {
-		msg := fmt.Sprintf("Create volume attachment failed with state : %v", state)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
 	}
 
 	volumeResp := model.CreateVolumeAttachmentResponse(resp, constants.CREATE)
@@ -366,34 +433,25 @@ func (cli *Client) CreateVolumeAttachment(
 	//return &model.VolumeAttachment{}, errors.New("not implemented")
 }
 
-func (cli *Client) DeleteVolumeAttachment(attachmentId string) (*model.VolumeAttachment, error) {
+func (cli *Client) DeleteVolumeAttachment(ctx context.Context, attachmentId string) (*model.VolumeAttachment, error) {
 	log.Infof("delete volume attachment id: %v", attachmentId)
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	httpResponse, err := r.VolumeAttachmentsApi.Delete(ctx, attachmentId)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("delete volume attachment", httpResponse, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Debugf("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	httpResponse, err := r.VolumeAttachmentsApi.Delete(ctx, attachmentId)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("delete volume attachment failed with error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("Delete volume attachment failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
 	if httpResponse.StatusCode != constants.STATUS_OK {
-		msg := fmt.Sprintf("Delete volume attachment failed with status code = %d", httpResponse.StatusCode)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
+		err = glm.StatusError("delete volume attachment", httpResponse, nil)
+		log.Errorln(err)
+		return nil, err
 	}
 
-	log.Infof("Delete volume attachment response %v", httpResponse)
+	log.Infof("Delete volume attachment response %v", redact.Value(httpResponse))
 	var resp glmClient.VolumeAttachment
 	resp.ID = attachmentId
 	resp.State = constants.STATE_DELETED
@@ -403,109 +461,136 @@ func (cli *Client) DeleteVolumeAttachment(attachmentId string) (*model.VolumeAtt
 	return volumeResp, nil
 }
 
-func (cli *Client) GetVolumeAttachment(attachmentId string) (*model.VolumeAttachment, error) {
+func (cli *Client) GetVolumeAttachment(ctx context.Context, attachmentId string) (*model.VolumeAttachment, error) {
 	log.Infof("get volume attachment id: %v", attachmentId)
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumeAttachmentsApi.GetByID(ctx, attachmentId)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("get volume attachment", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Debugf("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumeAttachmentsApi.GetByID(ctx, attachmentId)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("get volume attachment failed with error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("Get volume attachment failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-	log.Infof("Get volume attachment response %v", result)
+	log.Infof("Get volume attachment response %v", redact.Value(result))
 	getResp := model.CreateVolumeAttachmentResponse(result, constants.GET)
-	log.Infof("Get volume attachment response structure %+v", getResp)
+	log.Infof("Get volume attachment response structure %+v", redact.Value(getResp))
 	//covert get volume results into model.Volume and return it
 	return getResp, nil
 }
 
-func (cli *Client) ListVolumeAttachments() (*[]model.VolumeAttachment, error) {
+func (cli *Client) ListVolumeAttachments(ctx context.Context) (*[]model.VolumeAttachment, error) {
 	log.Infof("list volume attachments")
-	var errMsg errorMsg
-	ctx, r, err := GetREST(cli.Url, cli.UserName, cli.MembershipID)
+	r := cli.GetREST()
+	result, httpResp, err := r.VolumeAttachmentsApi.List(ctx)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
+		err = glm.APIClientError("list volume attachments", httpResp, err)
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("context value: %v\n", ctx)
-	log.Infof("r value: %v\n", r)
-	result, _, err := r.VolumeAttachmentsApi.List(ctx)
-	if err != nil && err.Error() == UndefinedResponseMsg {
-		msg := fmt.Sprintf("list volume attachment failed with error %+v", err)
-		log.Errorf(msg)
-		return nil, UndefinedResponseError
-	} else if err != nil {
-		_ = json.Unmarshal(err.(glmClient.GenericOpenAPIError).Body(), &errMsg)
-		msg := fmt.Sprintf("List volume attachments failed with error: %+v", errMsg.Message)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-	log.Infof("List volume attachment response %v", result)
+	log.Infof("List volume attachment response %v", redact.Value(result))
 	volumeAttachmentsList := []model.VolumeAttachment{}
 	for _, item := range result {
 		volumeAttachmentsList = append(volumeAttachmentsList, *model.CreateVolumeAttachmentResponse(item, constants.LIST))
 	}
-	log.Infof("List volume response structure %+v", volumeAttachmentsList)
+	log.Infof("List volume response structure %+v", redact.Value(volumeAttachmentsList))
 	return &volumeAttachmentsList, nil
 }
 
-func (cli *Client) ListVolumeFlavors() (*[]model.VolumeFlavor, error) {
-	path := ""
-	rawQuery := ""
-	client := restclient.NewRestClient()
-	userInfo := restclient.UserInfo{
-		UserName: cli.UserName,
-		UserPwd:  cli.Password,
-	}
-
-	header := map[string]string{
-		"Membership": cli.MembershipID,
-	}
-	sessionToken, err := model.GetSessionToken()
+func (cli *Client) ListVolumeFlavors(ctx context.Context) (*[]model.VolumeFlavor, error) {
+	var volumeFlavorResp []model.VolumeFlavor
+	err := cli.Transport.Do(ctx, &glm.Request{Operation: "list volume flavors", Method: http.MethodGet,
+		Path: constants.REST_VOLUME_FLAVOR_URL}, &volumeFlavorResp)
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
-	statusCode, responseBody, err := client.ExecuteRestRequest(constants.GET_FLAVOR, constants.REST_VOLUME_FLAVOR_URL,
-		path, rawQuery, userInfo, cli.Url, "", 0, nil, header, sessionToken)
+	return &volumeFlavorResp, nil
+}
+
+// WaitForVolumeState waits until the volume reaches state, using the wait
+// timeout of the client.
+func (cli *Client) WaitForVolumeState(ctx context.Context, volumeID string,
+	state glmClient.VolumeState) (*model.Volume, error) {
+	log.Infof("WaitForVolumeState volume id: %s state: %s", volumeID, state)
+	r := cli.GetREST()
+	result, err := cli.waitForVolume(ctx, r, volumeID, state)
 	if err != nil {
-		msg := fmt.Sprintf("volume flavor list failed with error: %+v\n", err)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-	log.Infof("responseBody %+v\n", string(responseBody))
-	if strings.TrimSuffix(string(responseBody), "\n") == "Token is expired" {
-		log.Errorf(string(responseBody))
-		return nil, TokenExpiredError
+		log.Errorln(err)
+		return nil, err
 	}
+	return model.CreateResponse(result, constants.GET), nil
+}
 
-	if statusCode != restclient.StatusCodeOk {
-		msg := fmt.Sprintf("Volume flavor list failed with status code: %+v\n", statusCode)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
+func (cli *Client) SetWaitOptions(opts model.WaitOptions) {
+	cli.WaitOptions = opts
+}
 
-	var volumeFlavorResp []model.VolumeFlavor
-	err = json.Unmarshal(responseBody, &volumeFlavorResp)
-	if err != nil {
-		msg := fmt.Sprintf("listVolumeFlavors: unmarshal: %v", err)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
+// waitForVolume polls the volume until it reaches state. A volume that fails
+// stops the wait. Context and session errors are returned unchanged so that
+// callers can tell them apart.
+func (cli *Client) waitForVolume(ctx context.Context, r *glmClient.APIClient, volumeID string,
+	state glmClient.VolumeState) (glmClient.Volume, error) {
+	var volume glmClient.Volume
+	err := NewWaiter(cli.WaitOptions.Timeout).Wait(ctx, func(ctx context.Context) (bool, error) {
+		result, httpResp, err := r.VolumesApi.GetByID(ctx, volumeID)
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil {
+			err = glm.APIClientError("get volume", httpResp, err)
+			// portals may no longer list a volume once it is deleted
+			if state == glmClient.VOLUMESTATE_DELETED && model.Category(err) == model.ErrorNotFound {
+				volume = glmClient.Volume{ID: volumeID, State: glmClient.VOLUMESTATE_DELETED}
+				return true, nil
+			}
+			return false, err
+		}
+		volume = result
+		log.Infof("volume %s state: %s", volumeID, result.State)
+		if result.State == glmClient.VOLUMESTATE_FAILED && state != glmClient.VOLUMESTATE_FAILED {
+			return false, fmt.Errorf("volume %s is in state %s", volumeID, result.State)
+		}
+		return result.State == state, nil
+	})
+	if err == WaitTimeoutError {
+		err = &model.APIError{Category: model.ErrorTimeout, Err: err,
+			Message: fmt.Sprintf("volume %s did not reach state %s within %v, current state: %s",
+				volumeID, state, NewWaiter(cli.WaitOptions.Timeout).Timeout, volume.State)}
+	}
+	return volume, err
+}
 
-	return &volumeFlavorResp, nil
+// waitForVolumeAttachment polls the volume attachment until it reaches state.
+// An attachment that fails stops the wait.
+func (cli *Client) waitForVolumeAttachment(ctx context.Context, r *glmClient.APIClient, attachmentID string,
+	state glmClient.VaStateEnum) (glmClient.VolumeAttachment, error) {
+	var attachment glmClient.VolumeAttachment
+	err := NewWaiter(cli.WaitOptions.Timeout).Wait(ctx, func(ctx context.Context) (bool, error) {
+		result, httpResp, err := r.VolumeAttachmentsApi.GetByID(ctx, attachmentID)
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil {
+			return false, glm.APIClientError("get volume attachment", httpResp, err)
+		}
+		attachment = result
+		log.Infof("volume attachment %s state: %s", attachmentID, result.State)
+		if result.State == glmClient.VASTATEENUM_FAILED && state != glmClient.VASTATEENUM_FAILED {
+			return false, fmt.Errorf("volume attachment %s is in state %s", attachmentID, result.State)
+		}
+		return result.State == state, nil
+	})
+	if err == WaitTimeoutError {
+		err = &model.APIError{Category: model.ErrorTimeout, Err: err,
+			Message: fmt.Sprintf("volume attachment %s did not reach state %s within %v, current state: %s",
+				attachmentID, state, NewWaiter(cli.WaitOptions.Timeout).Timeout, attachment.State)}
+	}
+	return attachment, err
 }
 
 func (cli *Client) GetGlmCredentials() (map[string]string, error) {
@@ -520,3 +605,19 @@ func (cli *Client) GetGlmCredentials() (map[string]string, error) {
 	glmCredentials["MEMBERSHIP_ID"] = cli.MembershipID
 	return glmCredentials, nil
 }
+
+// GetTransport returns the transport all GLM requests of the client are sent
+// through.
+func (cli *Client) GetTransport() *glm.Transport {
+	return cli.Transport
+}
+
+// GetSessionToken returns the token of the session cached for this client's
+// portal, user and membership, refreshing it first when it is about to expire.
+func (cli *Client) GetSessionToken(ctx context.Context) (string, error) {
+	return model.GetSessionToken(ctx, cli.sessionKey(), cli)
+}
+
+func (cli *Client) sessionKey() model.SessionKey {
+	return model.SessionKey{Portal: cli.Url, Username: cli.UserName, MembershipID: cli.MembershipID}
+}
@@ -0,0 +1,16 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package client
+
+import (
+	"errors"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+)
+
+// IsSessionError reports whether err means that the GLM session is missing
+// or no longer accepted, so that logging in again may help.
+func IsSessionError(err error) bool {
+	return errors.Is(err, model.TokenError) || errors.Is(err, glm.UndefinedResponseError) ||
+		errors.Is(err, glm.TokenExpiredError)
+}
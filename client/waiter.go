@@ -0,0 +1,76 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"time"
+)
+
+// WaitTimeoutError is returned by Waiter.Wait when the operation did not
+// complete within the wait timeout.
+var WaitTimeoutError = errors.New("timed out waiting for the operation to complete")
+
+// Waiter polls an asynchronous GLM operation with exponential backoff until it
+// completes, fails, the context is done or Timeout expires.
+type Waiter struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Timeout         time.Duration
+}
+
+// NewWaiter returns a waiter with the default backoff. A timeout of 0 selects
+// the default wait timeout.
+func NewWaiter(timeout time.Duration) *Waiter {
+	if timeout <= 0 {
+		timeout = constants.DEFAULT_WAIT_TIMEOUT * time.Second
+	}
+	return &Waiter{
+		InitialInterval: constants.WAIT_INITIAL_INTERVAL * time.Second,
+		MaxInterval:     constants.WAIT_MAX_INTERVAL * time.Second,
+		Multiplier:      constants.WAIT_MULTIPLIER,
+		Timeout:         timeout,
+	}
+}
+
+// Wait calls poll until it reports done or fails. The first poll is made
+// immediately; the interval between polls then grows up to MaxInterval.
+func (w *Waiter) Wait(ctx context.Context, poll func(ctx context.Context) (bool, error)) error {
+	deadline := time.Now().Add(w.Timeout)
+	interval := w.InitialInterval
+	for {
+		done, err := poll(ctx)
+		if err != nil || done {
+			return err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return WaitTimeoutError
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+		interval = time.Duration(float64(interval) * w.Multiplier)
+		if interval > w.MaxInterval {
+			interval = w.MaxInterval
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaiterBacksOff(t *testing.T) {
+	waiter := &Waiter{InitialInterval: 10 * time.Millisecond, MaxInterval: 40 * time.Millisecond,
+		Multiplier: 2, Timeout: time.Second}
+	var polls []time.Time
+	err := waiter.Wait(context.Background(), func(ctx context.Context) (bool, error) {
+		polls = append(polls, time.Now())
+		return len(polls) == 5, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, min := range want {
+		if gap := polls[i+1].Sub(polls[i]); gap < min {
+			t.Errorf("interval %d is %v, want at least %v", i, gap, min)
+		}
+	}
+}
+
+func TestWaiterStops(t *testing.T) {
+	waiter := &Waiter{InitialInterval: 10 * time.Millisecond, MaxInterval: 10 * time.Millisecond,
+		Multiplier: 2, Timeout: 50 * time.Millisecond}
+	notDone := func(ctx context.Context) (bool, error) { return false, nil }
+
+	if err := waiter.Wait(context.Background(), notDone); err != WaitTimeoutError {
+		t.Errorf("got %v, want WaitTimeoutError", err)
+	}
+
+	failed := errors.New("volume failed")
+	err := waiter.Wait(context.Background(), func(ctx context.Context) (bool, error) { return false, failed })
+	if err != failed {
+		t.Errorf("got %v, want the poll error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := waiter.Wait(ctx, notDone); err != context.Canceled {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
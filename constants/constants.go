@@ -27,6 +27,9 @@ const (
 	PASSWORD_REALM                         = "Username-Password-Authentication"
 	GRANT_PASSWORD_REALM                   = "http://auth0.com/oauth/grant-type/password-realm"
 	OPEN_ID                                = "openid"
+	OFFLINE_ACCESS                         = "offline_access"
+	GRANT_REFRESH_TOKEN                    = "refresh_token"
+	TOKEN_REFRESH_MARGIN                   = 300
 	LOCATION_ID                            = "location_id"
 	REST_API_VERSION                       = "/rest/v1"
 	GLM_PORTAL                             = "glmPortal"
@@ -34,7 +37,6 @@ const (
 	MIN_ARGS_LENGTH                 int    = 1
 	STATUS_OK                       int    = 200
 	VOLUME_STATE_DELETING                  = "deleting"
-	LOG_FILE                        string = "/tmp/singularity.log"
 	CREATE_VOL_TABLE_COLUMNS        int    = 6
 	TABLE_ROWS                      int    = 1
 	LIST_TABLE_COLUMNS              int    = 3
@@ -51,16 +53,11 @@ const (
 	FLAVOR_NAME                            = "name"
 	LIST_FLAVOR_TABLE_COLUMNS       int    = 2
 	REST_VOLUME_FLAVOR_URL          string = "rest/volumeflavors"
-	MAX_RETRY_COUNT                 int    = 5
-	MIN_RETRY_COUNT                 int    = 0
-	SLEEP_TIME                             = 5
-	GET_FLAVOR                             = "GET"
 	GLM_CLIENT_VERSION                     = "0.14.0"
 	FLAVORNAME                             = "flavor_name"
 	PASSWORD                               = "password"
 	USERNAME                               = "username"
 	REST_CAPACITYPOOLS_URL          string = "rest/capacitypools"
-	GET_CAPACITYPOOLS                      = "GET"
 	STATE_DELETED                          = "deleted"
 	MAPR_FUSE_CONF_FILE                    = "/opt/mapr/conf/fuse.conf"
 	VOLUME_ATTACHMENT_STATE_READY          = "ready"
@@ -69,4 +66,67 @@ const (
 	AUTHOR                                 = "HPE Team"
 	PLUGIN_DESCRIPTION                     = "CLI plugin to interface Singularity with Data Fabric"
 	SESSION_TOKEN                          = "sessionToken"
+	PASSWORD_STDIN                         = "password-stdin"
+	GLM_USERNAME_ENV                       = "GLM_USERNAME"
+	GLM_PASSWORD_ENV                       = "GLM_PASSWORD"
+	GLM_CREDENTIALS_FILE_ENV               = "GLM_CREDENTIALS_FILE"
+	USER_CREDENTIALS_FILE                  = "hpe-data-fabric/singularity/credentials"
+	CA_BUNDLE                              = "ca_bundle"
+	CLIENT_CERT                            = "client_cert"
+	CLIENT_KEY                             = "client_key"
+	INSECURE_SKIP_VERIFY                   = "insecure_skip_verify"
+	LOGGING                                = "logging"
+	LOG_FILE                               = "log_file"
+	LOG_LEVEL                              = "log_level"
+	LOG_MAX_SIZE_MIB                       = "log_max_size_mib"
+	LOG_MAX_FILES                          = "log_max_files"
+	USER_SESSION_CACHE_FILE                = "hpe-data-fabric/singularity/sessions.json"
+	USER_LOG_FILE                          = "hpe-data-fabric/singularity/singularity.log"
+	XDG_STATE_HOME_ENV                     = "XDG_STATE_HOME"
+	DEBUG                                  = "debug"
+	TIMEOUT                                = "timeout"
+	GLM                                    = "glm"
+	LOGIN                                  = "login"
+	LOGOUT                                 = "logout"
+	WHOAMI                                 = "whoami"
+	SESSION_TABLE_COLUMNS           int    = 5
+	LOGOUT_TABLE_COLUMNS            int    = 2
+	WAIT                                   = "wait"
+	WAIT_TIMEOUT                           = "wait_timeout"
+	STATE                                  = "state"
+	DEFAULT_WAIT_TIMEOUT                   = 600
+	WAIT_INITIAL_INTERVAL                  = 2
+	WAIT_MAX_INTERVAL                      = 30
+	WAIT_MULTIPLIER                        = 2
+	VOLUME_STATE_ALLOCATED                 = "allocated"
+	REQUEST_ID_HEADER                      = "X-Request-Id"
+	MAX_RETRIES                            = "max_retries"
+	RETRY_INITIAL_DELAY                    = "retry_initial_delay"
+	RETRY_MAX_DELAY                        = "retry_max_delay"
+	DEFAULT_MAX_RETRIES                    = 3
+	DEFAULT_RETRY_INITIAL_DELAY            = 500
+	DEFAULT_RETRY_MAX_DELAY                = 30
+	MAX_RETRY_AFTER                        = 300
+	HTTP_DIAL_TIMEOUT                      = 30
+	HTTP_KEEP_ALIVE                        = 30
+	HTTP_TLS_HANDSHAKE_TIMEOUT             = 10
+	HTTP_RESPONSE_HEADER_TIMEOUT           = 60
+	HTTP_IDLE_CONN_TIMEOUT                 = 90
+	HTTP_MAX_IDLE_CONNS_PER_HOST           = 10
+	PROXY                                  = "proxy"
+	NO_PROXY                               = "no_proxy"
+	PROXY_USERNAME                         = "proxy_username"
+	PROXY_PASSWORD                         = "proxy_password"
+	EXTRA_HEADERS                          = "extra_headers"
+	TRACE                                  = "trace"
+	GLM_TRACE_FILE_ENV                     = "GLM_TRACE_FILE"
+	UPDATE                                 = "update"
+	UPDATE_VOL_TABLE_COLUMNS        int    = 5
+	FROM_VOLUME_ID                         = "from_volume_id"
+	LABELS                                 = "labels"
+	SELECTOR                               = "selector"
+	FILTER                                 = "filter"
+	SORT                                   = "sort"
+	LIMIT                                  = "limit"
+	OFFSET                                 = "offset"
 )
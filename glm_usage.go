@@ -0,0 +1,29 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package main
+
+const glmUsage = `glm <login|logout|whoami> [format] [username] [password] [password-stdin] [debug] [trace] [timeout]
+
+Options
+- login
+    Logs in to the GLM portal and caches the session for the following commands.
+- logout
+    Ends the cached GLM session. Use it on shared login nodes when you are done.
+- whoami
+    Shows the identity and expiry of the cached GLM session.
+- format
+    specifies the format of <login|logout|whoami> response. format having two values "json" or "table".
+    If format is not mentioned in the commandline then default format value will be "table".
+- username
+	specifies GLM username. Required for <login> only, not required when set by GLM_USERNAME or the credentials file
+- password
+	specifies GLM password. Required for <login> only, not required when set by GLM_PASSWORD, password-stdin or the credentials file
+- password-stdin
+	if set to "true" the GLM password is read from the first line of stdin
+- debug
+	if set to "true" the plugin log is written at debug level, including the GLM API requests and responses
+- trace
+	file to which every HTTP exchange with GLM is appended as a JSON line
+- timeout
+	bounds the whole operation, e.g. "90s" or "10m" (a plain number is read as seconds). Ctrl-C and SIGTERM also stop it
+`
@@ -3,50 +3,21 @@
 package capacity_pool
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
-	"github.com/hpe-hcss/lh-cdc-singularity/restclient"
 	log "github.com/hpe-storage/common-host-libs/logger"
+	"net/http"
 )
 
-func GetCapacityPool(glmUsername string, glmPassword string, sessionToken string,
-	membershipID string, glmUrl string, capacityPoolID string) (*model.CapacityPools, error) {
-	path := ""
-	rawQuery := ""
-	client := restclient.NewRestClient()
-	userInfo := restclient.UserInfo{
-		UserName: glmUsername,
-		UserPwd:  glmPassword,
-	}
-
-	header := map[string]string{
-		"Membership": membershipID,
-	}
-	getCapacityPoolsUrl := constants.REST_CAPACITYPOOLS_URL + "/" + capacityPoolID
-	statusCode, responseBody, err := client.ExecuteRestRequest(constants.GET_CAPACITYPOOLS,
-		getCapacityPoolsUrl, path, rawQuery, userInfo, glmUrl, "", 0, nil,
-		header, sessionToken)
-	if err != nil {
-		msg := fmt.Sprintf("get capacitypools failed with error: %+v\n", err)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-	log.Infof("responseBody %+v\n", string(responseBody))
-	if statusCode != restclient.StatusCodeOk {
-		msg := fmt.Sprintf("get capacitypools failed with status code: %+v\n", statusCode)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
-	}
-
+func GetCapacityPool(ctx context.Context, transport *glm.Transport, capacityPoolID string) (*model.CapacityPools, error) {
 	var capacityPoolsResp model.CapacityPools
-	err = json.Unmarshal(responseBody, &capacityPoolsResp)
+	err := transport.Do(ctx, &glm.Request{Operation: "get capacitypools", Method: http.MethodGet,
+		Path: constants.REST_CAPACITYPOOLS_URL + "/" + capacityPoolID}, &capacityPoolsResp)
 	if err != nil {
-		msg := fmt.Sprintf("capacitypools get Unmarshalling failed with error %v\n", err)
-		log.Errorf(msg)
-		return nil, errors.New(msg)
+		log.Errorln(err)
+		return nil, err
 	}
 	log.Infof("Get CapacityPool response %+v", capacityPoolsResp)
 	return &capacityPoolsResp, nil
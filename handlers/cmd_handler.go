@@ -3,27 +3,32 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
+	glm "github.com/hpe-hcss/lh-cdc-singularity/handlers/glm"
 	volume "github.com/hpe-hcss/lh-cdc-singularity/handlers/volume"
 	volumeAttachment "github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_attachment"
 	volumeFlavor "github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_flavors"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
+// CmdHandlerFactory creates the handler of the commands of a resource type.
 type CmdHandlerFactory interface {
-	Create(args []string) *CmdHandler
+	Create(resourceType string, args []string) (CmdHandler, error)
 }
 
 type CmdHandlerFactoryImpl struct {
 }
 
 type CmdHandler interface {
-	// Handle -glmCredentials is a key value pairs of glm info parameters i.e. glmPortal and membershipId
+	// Handle -ctx is cancelled on SIGINT/SIGTERM and when the timeout= argument expires.
+	// -glmCredentials is a key value pairs of glm info parameters i.e. glmPortal and membershipId
 	// saved in plugin.conf
 	// -argsMap is a key value pairs of the arguments passed in command line.
 
-	Handle(glmCredentials map[string]string, argsMap map[string]interface{}) (interface{}, error)
+	Handle(ctx context.Context, glmCredentials map[string]string, argsMap map[string]interface{}) (interface{}, error)
 }
 
 func (ch *CmdHandlerFactoryImpl) Create(resourceType string, args []string) (CmdHandler, error) {
@@ -34,7 +39,9 @@ func (ch *CmdHandlerFactoryImpl) Create(resourceType string, args []string) (Cmd
 		return volumeAttachment.NewCmdHandlerVolumeAttachment(args), nil
 	} else if resourceType == constants.VOLUME_FLAVORS {
 		return volumeFlavor.NewCmdHandlerVolumeFlavor(args), nil
+	} else if resourceType == constants.GLM {
+		return glm.NewCmdHandlerGLM(args), nil
 	} else {
-		return nil, fmt.Errorf("invalid resource type")
+		return nil, model.ValidationError(fmt.Errorf("invalid resource type"))
 	}
 }
@@ -0,0 +1,115 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glmtest"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// handle runs a command the way the plugin does, against the fake portal.
+func handle(s *glmtest.Server, resourceType string, args ...string) (interface{}, error) {
+	ch := &CmdHandlerFactoryImpl{}
+	cmdHandler, err := ch.Create(resourceType, args)
+	if err != nil {
+		return nil, err
+	}
+	argsMap, err := utils.MakeCommand(append(args[1:], "username="+glmtest.Username,
+		"password="+glmtest.Password))
+	if err != nil {
+		return nil, err
+	}
+	return cmdHandler.Handle(context.Background(), s.CredDetails(), argsMap)
+}
+
+func TestHandlersEndToEnd(t *testing.T) {
+	glmtest.UseTempSessionCache(t)
+	t.Setenv(constants.GLM_USERNAME_ENV, "")
+	t.Setenv(constants.GLM_PASSWORD_ENV, "")
+	s := glmtest.NewServer(t)
+	volumeID := "00000000-0000-4000-8000-000000000001"
+	attachmentID := "00000000-0000-4000-8000-000000000002"
+	copyID := "00000000-0000-4000-8000-000000000003"
+
+	steps := []struct {
+		resourceType string
+		args         []string
+		// want are substrings of the response printed with %+v
+		want []string
+	}{
+		{constants.GLM, []string{"login"}, []string{"Username:" + glmtest.Username, "State:active"}},
+		{constants.VOLUME_FLAVORS, []string{"list"}, []string{"Name:" + glmtest.FlavorName}},
+		{constants.VOLUME, []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+			"flavor_name=" + glmtest.FlavorName, "description=scratch", "labels=team=genomics,env=dev"},
+			[]string{"VolumeID:" + volumeID, "State:allocated", "Labels:env=dev,team=genomics"}},
+		{constants.VOLUME, []string{"get", "volume_id=" + volumeID},
+			[]string{"Name:vol1", "Capacity:1", "Description:scratch ", "Labels:env=dev,team=genomics"}},
+		{constants.VOLUME, []string{"list"}, []string{"VolumeID:" + volumeID}},
+		{constants.VOLUME, []string{"list", "selector=team=genomics,env!=prod"}, []string{"VolumeID:" + volumeID}},
+		{constants.VOLUME, []string{"list", "selector=team=physics"}, []string{"[]"}},
+		{constants.VOLUME, []string{"update", "volume_id=" + volumeID, "capacity=2", "labels=team=genomics"},
+			[]string{"Capacity:2", "State:allocated", "PreviousCapacity:1", "Description:scratch ",
+				"Labels:team=genomics"}},
+		{constants.VOLUME_ATTACHMENT, []string{"create", "name=att1", "volume_id=" + volumeID},
+			[]string{"AttachmentID:" + attachmentID, "State:ready"}},
+		{constants.VOLUME, []string{"wait", "volume_id=" + volumeID, "state=visible"}, []string{"State:visible"}},
+		{constants.VOLUME_ATTACHMENT, []string{"get", "attachment_id=" + attachmentID},
+			[]string{"VolumeID:" + volumeID, "State:ready"}},
+		{constants.VOLUME_ATTACHMENT, []string{"list"}, []string{"AttachmentID:" + attachmentID}},
+		{constants.VOLUME_ATTACHMENT, []string{"delete", "attachment_id=" + attachmentID},
+			[]string{"State:deleted"}},
+		{constants.VOLUME, []string{"create", "from_volume_id=" + volumeID, "name=vol3", "description=copy"},
+			[]string{"Name:vol3", "VolumeID:" + copyID, "Capacity:2", "State:allocated", "Labels:team=genomics"}},
+		{constants.VOLUME, []string{"list", "filter=name=vol*,capacity=2", "sort=name:desc", "limit=2"},
+			[]string{"[{Name:vol3 VolumeID:" + copyID, "{Name:vol1 VolumeID:" + volumeID}},
+		{constants.VOLUME, []string{"delete", "volume_id=" + copyID}, []string{"State:deleted"}},
+		{constants.VOLUME, []string{"delete", "volume_id=" + volumeID}, []string{"State:deleted"}},
+		{constants.GLM, []string{"whoami"}, []string{"Username:" + glmtest.Username}},
+		{constants.GLM, []string{"logout"}, []string{"State:logged out"}},
+	}
+	for _, step := range steps {
+		resp, err := handle(s, step.resourceType, step.args...)
+		if err != nil {
+			t.Fatalf("%s %v: %v", step.resourceType, step.args, err)
+		}
+		got := fmt.Sprintf("%+v", resp)
+		if v := reflect.ValueOf(resp); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Slice {
+			got = fmt.Sprintf("%+v", v.Elem().Interface())
+		}
+		for _, want := range step.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s %v: got %s, want %s", step.resourceType, step.args, got, want)
+			}
+		}
+	}
+}
+
+func TestHandlersLogInAgainWhenTheSessionExpires(t *testing.T) {
+	glmtest.UseTempSessionCache(t)
+	s := glmtest.NewServer(t)
+	volume := s.AddVolume("vol1", 1)
+	for _, expire := range []bool{false, true} {
+		if expire {
+			s.ExpireSessions()
+		}
+		if _, err := handle(s, constants.VOLUME, "get", "volume_id="+volume.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{constants.GRANT_PASSWORD_REALM, constants.GRANT_PASSWORD_REALM}
+	if got := s.Grants(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got grants %v, want a login before the first command and after the expiry", got)
+	}
+
+	_, err := handle(s, constants.VOLUME, "get", "volume_id=missing")
+	if model.Category(err) != model.ErrorNotFound {
+		t.Errorf("get missing volume: got %v", err)
+	}
+}
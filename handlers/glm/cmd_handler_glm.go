@@ -0,0 +1,92 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type SessionHandler interface {
+	MakeResource(string, map[string]interface{}) (*model.Session, error)
+	// NeedsCredentials reports whether Execute talks to the auth service and
+	// therefore needs the GLM password. Otherwise only a username that is
+	// available without prompting is used to pick the cached session.
+	NeedsCredentials() bool
+	Execute(context.Context, *model.Session, client.ClientInterface) (interface{}, error)
+}
+
+type CmdHandlerGLM struct {
+	args  []string
+	opMap map[string]SessionHandler
+}
+
+var supportedGLMOperations = []string{"login", "logout", "whoami"}
+
+func NewCmdHandlerGLM(args []string) *CmdHandlerGLM {
+	log.Infof("NewCmdHandlerGLM : %v", redact.Args(args))
+	return &CmdHandlerGLM{
+		opMap: map[string]SessionHandler{
+			constants.LOGIN:  &LoginHandler{},
+			constants.LOGOUT: &LogoutHandler{},
+			constants.WHOAMI: &WhoamiHandler{},
+		},
+		args: args,
+	}
+}
+
+func (ch *CmdHandlerGLM) Handle(ctx context.Context, glmCredDetails map[string]string,
+	argsMap map[string]interface{}) (interface{}, error) {
+	log.Infof("Handle function")
+	operation := ch.args[0]
+	if err := utils.ValidateOperations(operation, supportedGLMOperations); err != nil {
+		log.Errorln(err)
+		return nil, model.ValidationError(err)
+	}
+
+	handler := ch.opMap[operation]
+	if handler == nil {
+		msg := fmt.Sprintf("unsupported sub-command: %s", operation)
+		log.Errorln(msg)
+		return nil, model.ValidationError(errors.New(msg))
+	}
+	session, err := handler.MakeResource(operation, argsMap)
+	if err != nil {
+		log.Errorln(err)
+		return nil, model.ValidationError(err)
+	}
+	session.Portal = glmCredDetails[constants.GLM_PORTAL]
+	session.MembershipID = glmCredDetails[constants.MEMBERSHIP_ID]
+
+	glmUserName, glmPassword := utils.GetUsername(argsMap), ""
+	if handler.NeedsCredentials() {
+		glmUserName, glmPassword, err = utils.GetCredentials(argsMap)
+		if err != nil {
+			return nil, model.ValidationError(err)
+		}
+	}
+	cli, err := client.NewClientFromConfig(glmCredDetails, glmUserName, glmPassword)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler.Execute(ctx, session, cli)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func makeSession(operation string, argsMap map[string]interface{}) (*model.Session, error) {
+	if err := model.ValidateSessionArguments(operation, argsMap); err != nil {
+		return nil, err
+	}
+	return &model.Session{}, nil
+}
@@ -0,0 +1,31 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type LoginHandler struct{}
+
+func (ch *LoginHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Session, error) {
+	return makeSession(operation, argsMap)
+}
+
+func (ch *LoginHandler) NeedsCredentials() bool {
+	return true
+}
+
+func (ch *LoginHandler) Execute(ctx context.Context, session *model.Session, cli client.ClientInterface) (interface{}, error) {
+	log.Infof("glm login")
+	if err := cli.Login(ctx); err != nil {
+		err = fmt.Errorf("Session creation failed with error: %w", err)
+		log.Errorln(err)
+		return nil, err
+	}
+	return cli.GetSession()
+}
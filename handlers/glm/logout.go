@@ -0,0 +1,29 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type LogoutHandler struct{}
+
+func (ch *LogoutHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Session, error) {
+	return makeSession(operation, argsMap)
+}
+
+func (ch *LogoutHandler) NeedsCredentials() bool {
+	return false
+}
+
+func (ch *LogoutHandler) Execute(ctx context.Context, session *model.Session, cli client.ClientInterface) (interface{}, error) {
+	log.Infof("glm logout")
+	if err := cli.Logout(ctx); err != nil {
+		return nil, err
+	}
+	session.State = model.SessionStateLoggedOut
+	return session, nil
+}
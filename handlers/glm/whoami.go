@@ -0,0 +1,30 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"errors"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type WhoamiHandler struct{}
+
+func (ch *WhoamiHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Session, error) {
+	return makeSession(operation, argsMap)
+}
+
+func (ch *WhoamiHandler) NeedsCredentials() bool {
+	return false
+}
+
+func (ch *WhoamiHandler) Execute(ctx context.Context, session *model.Session, cli client.ClientInterface) (interface{}, error) {
+	log.Infof("glm whoami")
+	resp, err := cli.GetSession()
+	if err == model.TokenError {
+		return nil, model.NewError(model.ErrorUnauthorized, errors.New("not logged in to GLM, run 'singularity glm login'"))
+	}
+	return resp, err
+}
@@ -3,11 +3,14 @@
 package volume
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	"github.com/hpe-hcss/lh-cdc-singularity/utils"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
@@ -15,7 +18,7 @@ import (
 type VolumeHandler interface {
 	MakeResource(string, map[string]interface{}) (*model.Volume, error)
 	ValidateResource(string, *model.Volume) error
-	Execute(*model.Volume, client.ClientInterface) (interface{}, error)
+	Execute(context.Context, *model.Volume, client.ClientInterface) (interface{}, error)
 }
 
 type CmdHandlerVolume struct {
@@ -23,30 +26,36 @@ type CmdHandlerVolume struct {
 	opMap map[string]VolumeHandler
 }
 
-var supportedVolumeOperations = []string{"create", "get", "delete", "list"}
+var supportedVolumeOperations = []string{"create", "get", "delete", "list", "wait", "update"}
+
+// newClient creates the client volume commands are executed with. Tests replace
+// it with a mock.
+var newClient = client.NewClientFromConfig
 
 func NewCmdHandlerVolume(args []string) *CmdHandlerVolume {
-	log.Infof("NewCmdHandlerVolume : %v", args)
+	log.Infof("NewCmdHandlerVolume : %v", redact.Args(args))
 	ch := &CmdHandlerVolume{}
 	opMap := map[string]VolumeHandler{
 		constants.CREATE: &CreateVolumeHandler{},
 		constants.DELETE: &DeleteVolumeHandler{},
 		constants.GET:    &GetVolumeHandler{},
 		constants.LIST:   &ListVolumeHandler{},
+		constants.WAIT:   &WaitVolumeHandler{},
+		constants.UPDATE: &UpdateVolumeHandler{},
 	}
 	ch.args = args
 	ch.opMap = opMap
 	return ch
 }
 
-func (ch *CmdHandlerVolume) Handle(glmCredDetails map[string]string,
+func (ch *CmdHandlerVolume) Handle(ctx context.Context, glmCredDetails map[string]string,
 	argsMap map[string]interface{}) (interface{}, error) {
 	log.Infof("Handle function")
 	var resp interface{}
 	operation := ch.args[0]
 	if err := utils.ValidateOperations(operation, supportedVolumeOperations); err != nil {
 		log.Errorln(err)
-		return nil, err
+		return nil, model.ValidationError(err)
 	}
 
 	handler := ch.opMap[operation]
@@ -55,33 +64,41 @@ func (ch *CmdHandlerVolume) Handle(glmCredDetails map[string]string,
 		volume, err := handler.MakeResource(operation, argsMap)
 		if err != nil {
 			log.Errorln(err)
-			return nil, err
+			return nil, model.ValidationError(err)
 		}
 
 		err = handler.ValidateResource(operation, volume)
 		if err != nil {
 			log.Errorln(err)
-			return nil, err
+			return nil, model.ValidationError(err)
+		}
+
+		waitOpts, err := utils.GetWaitOptions(argsMap)
+		if err != nil {
+			log.Errorln(err)
+			return nil, model.ValidationError(err)
 		}
 
 		glmUserName, glmPassword, err := utils.GetCredentials(argsMap)
 		if err != nil {
-			return nil, err
+			return nil, model.ValidationError(err)
 		}
 
-		cli := client.NewClient(glmCredDetails[constants.GLM_PORTAL], glmUserName,
-			glmPassword, glmCredDetails[constants.MEMBERSHIP_ID])
-		resp, err = handler.Execute(volume, cli)
-		if err == model.TokenError || err == client.UndefinedResponseError || err == client.TokenExpiredError {
+		cli, err := newClient(glmCredDetails, glmUserName, glmPassword)
+		if err != nil {
+			return nil, err
+		}
+		cli.SetWaitOptions(waitOpts)
+		resp, err = handler.Execute(ctx, volume, cli)
+		if client.IsSessionError(err) {
 			log.Errorf("Execute err %+v", err)
-			err := cli.Login()
+			err := cli.Login(ctx)
 			if err != nil {
-				msg := fmt.Sprintf("Session creation failed with error: %v", err)
-				log.Errorf(msg)
-				return nil, errors.New(msg)
+				err = fmt.Errorf("Session creation failed with error: %w", err)
+				log.Errorln(err)
+				return nil, err
 			}
-			defer cli.Logout()
-			resp, err = handler.Execute(volume, cli)
+			resp, err = handler.Execute(ctx, volume, cli)
 			if err != nil {
 				log.Errorln(err)
 				return nil, err
@@ -93,7 +110,7 @@ func (ch *CmdHandlerVolume) Handle(glmCredDetails map[string]string,
 	} else {
 		msg := fmt.Sprintf("unsupported sub-command: %s", operation)
 		log.Errorln(msg)
-		return nil, errors.New(msg)
+		return nil, model.ValidationError(errors.New(msg))
 	}
 	return resp, nil
 }
@@ -104,6 +121,14 @@ func ValidateCreateVolumeRequest(volume *model.Volume) error {
 		msg := fmt.Sprintf("invalid value of volume %s is provided", constants.VOLUME_NAME)
 		log.Errorln(msg)
 		return errors.New(msg)
+	} else if volume.FromVolumeID != "" {
+		// the flavor, capacity and location are those of the source volume
+		if volume.Description == "" {
+			msg := fmt.Sprintf("%s is not provided", constants.DESCRIPTION)
+			log.Errorln(msg)
+			return errors.New(msg)
+		}
+		return nil
 	} else if volume.FlavorName == "" {
 		msg := fmt.Sprintf("invalid value of %s is provided", constants.FLAVORNAME)
 		log.Errorln(msg)
@@ -170,6 +195,39 @@ func ValidateListVolumeRequest(volume *model.Volume) error {
 	return ValidateCommonVolParams(volume)
 }
 
+// waitVolumeStates are the volume states that 'volume wait' can wait for.
+var waitVolumeStates = []glmClient.VolumeState{glmClient.VOLUMESTATE_ALLOCATED, glmClient.VOLUMESTATE_VISIBLE,
+	glmClient.VOLUMESTATE_DELETED}
+
+func ValidateWaitVolumeRequest(volume *model.Volume) error {
+	log.Infof("ValidateWaitVolumeRequest function")
+	if err := ValidateGetVolumeRequest(volume); err != nil {
+		return err
+	}
+	for _, state := range waitVolumeStates {
+		if volume.State == state {
+			return nil
+		}
+	}
+	msg := fmt.Sprintf("invalid value of %s is provided, valid states are %v", constants.STATE, waitVolumeStates)
+	log.Errorln(msg)
+	return errors.New(msg)
+}
+
+func ValidateUpdateVolumeRequest(volume *model.Volume) error {
+	log.Infof("ValidateUpdateVolumeRequest function")
+	if volume.VolumeID == "" {
+		log.Errorln("volume ID is not provided")
+		return errors.New("volume ID is not provided")
+	} else if volume.Capacity == 0 && volume.Description == "" && volume.Labels == nil {
+		msg := fmt.Sprintf("%s, %s and/or %s is not provided", constants.VOLUME_CAPACITY, constants.DESCRIPTION,
+			constants.LABELS)
+		log.Errorln(msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
 func ValidateVolumeRequest(operation string, volume *model.Volume) error {
 	log.Infof("ValidateVolumeRequest function")
 	opMap := map[string]func(volume *model.Volume) error{
@@ -177,6 +235,8 @@ func ValidateVolumeRequest(operation string, volume *model.Volume) error {
 		constants.DELETE: ValidateGetVolumeRequest,
 		constants.GET:    ValidateGetVolumeRequest,
 		constants.LIST:   ValidateListVolumeRequest,
+		constants.WAIT:   ValidateWaitVolumeRequest,
+		constants.UPDATE: ValidateUpdateVolumeRequest,
 	}
 	return opMap[operation](volume)
 }
@@ -0,0 +1,415 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package volume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glmtest"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockclient"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockvolume"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testVolumeID = "5fb4a4a5-7a1c-4d4e-9a3c-2f0f1b0b6a01"
+	testCloneID  = "c3a9e0d2-8f41-4b6e-a5d7-0e2b9c1f7a04"
+)
+
+// loggedInTransport returns the transport of a client logged in to a fake
+// portal, for the handlers that send requests of their own.
+func loggedInTransport(t *testing.T) *glm.Transport {
+	glmtest.UseTempSessionCache(t)
+	s := glmtest.NewServer(t)
+	cli, err := client.NewClientFromConfig(s.CredDetails(), glmtest.Username, glmtest.Password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Login(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return cli.GetTransport()
+}
+
+func TestVolumeHandlers(t *testing.T) {
+	flavors := &[]model.VolumeFlavor{{ID: glmtest.FlavorID, Name: glmtest.FlavorName}}
+	allocated := &model.Volume{Name: "vol1", VolumeID: testVolumeID, FlavorID: glmtest.FlavorID, Capacity: 1,
+		LocationID: glmtest.LocationID, State: glmClient.VOLUMESTATE_ALLOCATED}
+	resized := &model.Volume{Name: "vol1", VolumeID: testVolumeID, Capacity: 2, PreviousCapacity: 1,
+		State: glmClient.VOLUMESTATE_ALLOCATED}
+	clone := &model.Volume{Name: "vol2", VolumeID: testCloneID, FlavorID: glmtest.FlavorID, Capacity: 1,
+		LocationID: glmtest.LocationID, State: glmClient.VOLUMESTATE_ALLOCATED}
+	labeled := &model.Volume{Name: "vol3", VolumeID: "labeled", FlavorID: glmtest.FlavorID,
+		Labels: model.Labels{"team": "genomics", "env": "dev"}}
+	notFound := model.NewError(model.ErrorNotFound, errors.New("volume not found"))
+
+	tests := []struct {
+		name      string
+		args      []string
+		expect    func(cli *mockclient.MockClientInterface)
+		want      interface{}
+		wantError string
+	}{
+		{name: "create",
+			args: []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+				"flavor_name=" + glmtest.FlavorName, "description=scratch"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(flavors, nil)
+				cli.EXPECT().CreateVolume(gomock.Any(), &model.Volume{Name: "vol1", Capacity: 1,
+					LocationID: glmtest.LocationID, FlavorName: glmtest.FlavorName, FlavorID: glmtest.FlavorID,
+					Description: "scratch"}).Return(allocated, nil)
+			},
+			want: allocated},
+		{name: "create with labels",
+			args: []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+				"flavor_name=" + glmtest.FlavorName, "description=scratch", "labels=team=genomics,env=dev"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(flavors, nil)
+				cli.EXPECT().CreateVolume(gomock.Any(), &model.Volume{Name: "vol1", Capacity: 1,
+					LocationID: glmtest.LocationID, FlavorName: glmtest.FlavorName, FlavorID: glmtest.FlavorID,
+					Description: "scratch", Labels: model.Labels{"team": "genomics", "env": "dev"}}).Return(
+					allocated, nil)
+			},
+			want: allocated},
+		{name: "create with invalid labels",
+			args: []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+				"flavor_name=" + glmtest.FlavorName, "description=scratch", "labels=team"},
+			wantError: "invalid value of labels is provided"},
+		{name: "create with unknown flavor",
+			args: []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+				"flavor_name=Fast", "description=scratch"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(flavors, nil)
+			},
+			wantError: "Volume flavor ID with flavor name Fast not found."},
+		{name: "create without description",
+			args: []string{"create", "name=vol1", "capacity=1", "location_id=" + glmtest.LocationID,
+				"flavor_name=" + glmtest.FlavorName},
+			wantError: "Argument 'description' is missing"},
+		{name: "create without capacity",
+			args: []string{"create", "name=vol1", "capacity=0", "location_id=" + glmtest.LocationID,
+				"flavor_name=" + glmtest.FlavorName, "description=scratch"},
+			wantError: "invalid value of capacity is provided"},
+		{name: "create with unknown argument",
+			args:      []string{"create", "name=vol1", "size=1"},
+			wantError: "Invalid argument size"},
+		{name: "get",
+			args: []string{"get", "volume_id=" + testVolumeID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().GetVolume(gomock.Any(), testVolumeID).Return(allocated, nil)
+			},
+			want: allocated},
+		{name: "get missing volume",
+			args: []string{"get", "volume_id=missing"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().GetVolume(gomock.Any(), "missing").Return(nil, notFound)
+			},
+			wantError: "volume not found"},
+		{name: "get without volume id",
+			args:      []string{"get"},
+			wantError: "Argument 'volume_id' is missing"},
+		{name: "delete",
+			args: []string{"delete", "volume_id=" + testVolumeID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().DeleteVolume(gomock.Any(), testVolumeID).Return(
+					&model.Volume{VolumeID: testVolumeID, State: constants.STATE_DELETED}, nil)
+			},
+			want: &model.Volume{VolumeID: testVolumeID, State: constants.STATE_DELETED}},
+		{name: "list",
+			args: []string{"list"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumes(gomock.Any()).Return(&[]model.Volume{*allocated,
+					{Name: "other", VolumeID: "other", FlavorID: "unknown"}}, nil)
+				cli.EXPECT().GetTransport().Return(loggedInTransport(t))
+			},
+			// volumes of flavors without a capacity pool are left out
+			want: &[]model.Volume{*allocated}},
+		{name: "list with selector",
+			args: []string{"list", "selector=team=genomics,env!=prod"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumes(gomock.Any()).Return(&[]model.Volume{*allocated, *labeled}, nil)
+				cli.EXPECT().GetTransport().Return(loggedInTransport(t))
+			},
+			want: &[]model.Volume{*labeled}},
+		{name: "list with invalid selector",
+			args:      []string{"list", "selector=team=gen omics"},
+			wantError: "invalid value of selector is provided"},
+		{name: "list with filter, sort and limit",
+			args: []string{"list", "filter=state=allocated,name=vol*", "sort=name:desc", "limit=1"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumes(gomock.Any()).Return(&[]model.Volume{*allocated, *labeled, *clone}, nil)
+				cli.EXPECT().GetTransport().Return(loggedInTransport(t))
+			},
+			// labeled is filtered out, having no state
+			want: &[]model.Volume{*clone}},
+		{name: "list with offset past the end",
+			args: []string{"list", "offset=3"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumes(gomock.Any()).Return(&[]model.Volume{*allocated, *clone}, nil)
+				cli.EXPECT().GetTransport().Return(loggedInTransport(t))
+			},
+			want: &[]model.Volume{}},
+		{name: "list with invalid limit",
+			args:      []string{"list", "limit=0"},
+			wantError: "invalid value of limit is provided"},
+		{name: "get with filter",
+			args:      []string{"get", "volume_id=" + testVolumeID, "filter=name=vol1"},
+			wantError: "Invalid argument filter"},
+		{name: "list with volume id",
+			args:      []string{"list", "volume_id=" + testVolumeID},
+			wantError: "Invalid argument volume_id"},
+		{name: "wait",
+			args: []string{"wait", "volume_id=" + testVolumeID, "state=Visible"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().WaitForVolumeState(gomock.Any(), testVolumeID, glmClient.VOLUMESTATE_VISIBLE).Return(
+					allocated, nil)
+			},
+			want: allocated},
+		{name: "wait for a transient state",
+			args:      []string{"wait", "volume_id=" + testVolumeID, "state=allocating"},
+			wantError: "invalid value of state is provided"},
+		{name: "update",
+			args: []string{"update", "volume_id=" + testVolumeID, "capacity=2", "description=nightly"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().UpdateVolume(gomock.Any(), &model.Volume{VolumeID: testVolumeID, Capacity: 2,
+					Description: "nightly"}).Return(resized, nil)
+			},
+			want: resized},
+		{name: "update description",
+			args: []string{"update", "volume_id=" + testVolumeID, "description=nightly"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().UpdateVolume(gomock.Any(), &model.Volume{VolumeID: testVolumeID,
+					Description: "nightly"}).Return(allocated, nil)
+			},
+			want: allocated},
+		{name: "update labels",
+			args: []string{"update", "volume_id=" + testVolumeID, "labels=team=genomics"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().UpdateVolume(gomock.Any(), &model.Volume{VolumeID: testVolumeID,
+					Labels: model.Labels{"team": "genomics"}}).Return(allocated, nil)
+			},
+			want: allocated},
+		{name: "update removing labels",
+			args: []string{"update", "volume_id=" + testVolumeID, "labels="},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().UpdateVolume(gomock.Any(), &model.Volume{VolumeID: testVolumeID,
+					Labels: model.Labels{}}).Return(allocated, nil)
+			},
+			want: allocated},
+		{name: "update that cannot shrink",
+			args: []string{"update", "volume_id=" + testVolumeID, "capacity=1"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().UpdateVolume(gomock.Any(), gomock.Any()).Return(nil,
+					model.ValidationError(errors.New("capacity of volume can only grow, it is 2")))
+			},
+			wantError: "can only grow"},
+		{name: "update without changes",
+			args:      []string{"update", "volume_id=" + testVolumeID},
+			wantError: "capacity, description and/or labels is not provided"},
+		{name: "update to no capacity",
+			args:      []string{"update", "volume_id=" + testVolumeID, "capacity=0"},
+			wantError: "invalid value of capacity is provided"},
+		{name: "create from volume",
+			args: []string{"create", "from_volume_id=" + testVolumeID, "name=vol2", "description=copy"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				gomock.InOrder(
+					cli.EXPECT().GetVolume(gomock.Any(), testVolumeID).Return(labeled, nil),
+					cli.EXPECT().CreateVolume(gomock.Any(), &model.Volume{Name: "vol2", Description: "copy",
+						FromVolumeID: testVolumeID, FlavorID: glmtest.FlavorID,
+						Labels: model.Labels{"team": "genomics", "env": "dev"}}).Return(clone, nil),
+				)
+			},
+			want: clone},
+		{name: "create from volume with labels",
+			args: []string{"create", "from_volume_id=" + testVolumeID, "name=vol2", "description=copy",
+				"labels=env=prod"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				gomock.InOrder(
+					cli.EXPECT().GetVolume(gomock.Any(), testVolumeID).Return(allocated, nil),
+					cli.EXPECT().CreateVolume(gomock.Any(), &model.Volume{Name: "vol2", Description: "copy",
+						FromVolumeID: testVolumeID, FlavorID: glmtest.FlavorID, LocationID: glmtest.LocationID,
+						Capacity: 1, Labels: model.Labels{"env": "prod"}}).Return(clone, nil),
+				)
+			},
+			want: clone},
+		{name: "create from missing volume",
+			args: []string{"create", "from_volume_id=" + testVolumeID, "name=vol2", "description=copy"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().GetVolume(gomock.Any(), testVolumeID).Return(nil, notFound)
+			},
+			wantError: "volume not found"},
+		{name: "create from volume without name",
+			args:      []string{"create", "from_volume_id=" + testVolumeID, "name=", "description=copy"},
+			wantError: "invalid value of volume name is provided"},
+		{name: "create from volume without description",
+			args:      []string{"create", "from_volume_id=" + testVolumeID, "name=vol2"},
+			wantError: "Argument 'description' is missing"},
+		{name: "create from volume with capacity",
+			args: []string{"create", "from_volume_id=" + testVolumeID, "name=vol2", "description=copy",
+				"capacity=5"},
+			wantError: "Invalid argument capacity"},
+		{name: "update name",
+			args:      []string{"update", "volume_id=" + testVolumeID, "name=vol2"},
+			wantError: "Invalid argument name"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			if tc.expect != nil {
+				tc.expect(cli)
+			}
+			argsMap, err := utils.MakeCommand(tc.args[1:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			operation := tc.args[0]
+			handler := NewCmdHandlerVolume(tc.args).opMap[operation]
+
+			resp, err := func() (interface{}, error) {
+				volume, err := handler.MakeResource(operation, argsMap)
+				if err != nil {
+					return nil, err
+				}
+				if err := handler.ValidateResource(operation, volume); err != nil {
+					return nil, err
+				}
+				return handler.Execute(context.Background(), volume, cli)
+			}()
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("got %+v, %v, want error %q", resp, err, tc.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resp, tc.want) {
+				t.Errorf("got %+v, want %+v", resp, tc.want)
+			}
+		})
+	}
+}
+
+func TestCmdHandlerVolumeLogsInAgain(t *testing.T) {
+	volume := &model.Volume{VolumeID: testVolumeID}
+	expired := model.NewError(model.ErrorUnauthorized, fmt.Errorf("get volume failed: %w", glm.TokenExpiredError))
+	notFound := model.NewError(model.ErrorNotFound, errors.New("volume not found"))
+
+	tests := []struct {
+		name string
+		// results are the errors of the first Execute and of the one after
+		// logging in again, if there is one
+		results      []error
+		login        error
+		wantCategory model.ErrorCategory
+		wantError    string
+	}{
+		{name: "success", results: []error{nil}},
+		{name: "no cached session", results: []error{model.TokenError, nil}},
+		{name: "session expired", results: []error{expired, nil}},
+		{name: "login fails", results: []error{model.TokenError}, login: errors.New("invalid credentials"),
+			wantError: "Session creation failed with error: invalid credentials"},
+		{name: "retry fails", results: []error{expired, notFound}, wantCategory: model.ErrorNotFound},
+		{name: "no login for other errors", results: []error{notFound}, wantCategory: model.ErrorNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			handler := mockvolume.NewMockVolumeHandler(ctrl)
+			saved := newClient
+			newClient = func(glmCredDetails map[string]string, username, password string) (client.ClientInterface,
+				error) {
+				if username != glmtest.Username || password != glmtest.Password {
+					t.Errorf("client created for %s:%s", username, password)
+				}
+				return cli, nil
+			}
+			defer func() { newClient = saved }()
+
+			handler.EXPECT().MakeResource(constants.GET, gomock.Any()).Return(volume, nil)
+			handler.EXPECT().ValidateResource(constants.GET, volume).Return(nil)
+			cli.EXPECT().SetWaitOptions(gomock.Any())
+			execute := func(result error) *gomock.Call {
+				if result != nil {
+					return handler.EXPECT().Execute(gomock.Any(), volume, cli).Return(nil, result)
+				}
+				return handler.EXPECT().Execute(gomock.Any(), volume, cli).Return(volume, nil)
+			}
+			calls := []*gomock.Call{execute(tc.results[0])}
+			if len(tc.results) > 1 || tc.login != nil {
+				calls = append(calls, cli.EXPECT().Login(gomock.Any()).Return(tc.login))
+			}
+			if len(tc.results) > 1 {
+				calls = append(calls, execute(tc.results[1]))
+			}
+			gomock.InOrder(calls...)
+
+			ch := &CmdHandlerVolume{args: []string{constants.GET}, opMap: map[string]VolumeHandler{
+				constants.GET: handler}}
+			resp, err := ch.Handle(context.Background(), map[string]string{}, map[string]interface{}{
+				constants.USERNAME: glmtest.Username, constants.PASSWORD: glmtest.Password})
+			switch {
+			case tc.wantError != "":
+				if err == nil || err.Error() != tc.wantError {
+					t.Errorf("got %v, want %s", err, tc.wantError)
+				}
+			case tc.wantCategory != "":
+				if model.Category(err) != tc.wantCategory {
+					t.Errorf("got %v, want a %s error", err, tc.wantCategory)
+				}
+			case err != nil || resp != volume:
+				t.Errorf("got %+v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func TestCmdHandlerVolumeRejectsInvalidCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		makeErr error
+		valErr  error
+	}{
+		{name: "unknown operation", args: []string{"resize"}},
+		{name: "invalid arguments", args: []string{constants.GET}, makeErr: errors.New("Invalid argument size")},
+		{name: "invalid volume", args: []string{constants.GET}, valErr: errors.New("volume ID is not provided")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			handler := mockvolume.NewMockVolumeHandler(ctrl)
+			saved := newClient
+			newClient = func(map[string]string, string, string) (client.ClientInterface, error) {
+				t.Error("client created for an invalid command")
+				return nil, errors.New("unexpected client")
+			}
+			defer func() { newClient = saved }()
+			if tc.makeErr != nil {
+				handler.EXPECT().MakeResource(constants.GET, gomock.Any()).Return(nil, tc.makeErr)
+			} else if tc.valErr != nil {
+				handler.EXPECT().MakeResource(constants.GET, gomock.Any()).Return(&model.Volume{}, nil)
+				handler.EXPECT().ValidateResource(constants.GET, gomock.Any()).Return(tc.valErr)
+			}
+
+			ch := &CmdHandlerVolume{args: tc.args, opMap: map[string]VolumeHandler{constants.GET: handler}}
+			_, err := ch.Handle(context.Background(), map[string]string{}, map[string]interface{}{})
+			if model.Category(err) != model.ErrorValidation {
+				t.Errorf("got %v, want a validation error", err)
+			}
+		})
+	}
+}
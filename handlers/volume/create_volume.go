@@ -3,18 +3,20 @@
 package volume
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	volumeFlavors "github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_flavors"
 	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
 type CreateVolumeHandler struct{}
 
 func (ch *CreateVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
-	log.Infof("Create volume args:%v\n", argsMap)
+	log.Infof("Create volume args:%v\n", redact.Map(argsMap))
 	volume, err := model.NewVolume(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -33,9 +35,12 @@ func (ch *CreateVolumeHandler) ValidateResource(operation string, volume *model.
 	return nil
 }
 
-func (ch *CreateVolumeHandler) Execute(volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+func (ch *CreateVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
 	log.Infof("create volume req:%v\n", volume)
-	volumeFlavorList, err := cli.ListVolumeFlavors()
+	if volume.FromVolumeID != "" {
+		return createFromVolume(ctx, volume, cli)
+	}
+	volumeFlavorList, err := cli.ListVolumeFlavors(ctx)
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
@@ -47,7 +52,31 @@ func (ch *CreateVolumeHandler) Execute(volume *model.Volume, cli client.ClientIn
 		return nil, errors.New(msg)
 	}
 	volume.FlavorID = flavorID
-	resp, err := cli.CreateVolume(volume) // model.Volume
+	resp, err := cli.CreateVolume(ctx, volume) // model.Volume
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	log.Infof("create volume response:%+v", resp)
+	return resp, nil
+}
+
+// createFromVolume creates the volume with the flavor, location, capacity and,
+// unless labels were given, the labels of the volume FromVolumeID. GLM has no
+// API to copy a volume, so its data is not copied.
+func createFromVolume(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+	source, err := cli.GetVolume(ctx, volume.FromVolumeID)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	volume.FlavorID = source.FlavorID
+	volume.LocationID = source.LocationID
+	volume.Capacity = source.Capacity
+	if volume.Labels == nil {
+		volume.Labels = source.Labels
+	}
+	resp, err := cli.CreateVolume(ctx, volume) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
@@ -3,15 +3,17 @@
 package volume
 
 import (
+	"context"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
 type DeleteVolumeHandler struct{}
 
 func (ch *DeleteVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
-	log.Infof("Delete volume args:%v\n", argsMap)
+	log.Infof("Delete volume args:%v\n", redact.Map(argsMap))
 	volume, err := model.NewVolume(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -30,9 +32,9 @@ func (ch *DeleteVolumeHandler) ValidateResource(operation string, volume *model.
 	return nil
 }
 
-func (ch *DeleteVolumeHandler) Execute(volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+func (ch *DeleteVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
 	log.Infof("delete volume:%v\n", volume.VolumeID)
-	resp, err := cli.DeleteVolume(volume.VolumeID) // model.Volume
+	resp, err := cli.DeleteVolume(ctx, volume.VolumeID) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
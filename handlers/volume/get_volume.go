@@ -3,12 +3,15 @@
 package volume
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
 	capacity_pool "github.com/hpe-hcss/lh-cdc-singularity/handlers/capacity_pool"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 	"io/ioutil"
 	"strings"
@@ -17,7 +20,7 @@ import (
 type GetVolumeHandler struct{}
 
 func (ch *GetVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
-	log.Infof("Get volume args:%v\n", argsMap)
+	log.Infof("Get volume args:%v\n", redact.Map(argsMap))
 	volume, err := model.NewVolume(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -36,9 +39,9 @@ func (ch *GetVolumeHandler) ValidateResource(operation string, volume *model.Vol
 	return nil
 }
 
-func (ch *GetVolumeHandler) Execute(volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+func (ch *GetVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
 	log.Infof("get volume:%v\n", volume.VolumeID)
-	resp, err := cli.GetVolume(volume.VolumeID) // model.Volume
+	resp, err := cli.GetVolume(ctx, volume.VolumeID) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
@@ -67,9 +70,9 @@ func GetMountedVolumeName(volume model.Volume) string {
 	return mountedVolPath
 }
 
-func GetVolumeResponseWithMountPath(resp *[]model.Volume, glmUsername string, glmPassword string, glmSessionToken string,
-	membershipID string, glmURL string) (*[]model.Volume, error) {
-	capacityPools, err := capacity_pool.ListCapacityPool(glmUsername, glmPassword, glmSessionToken, membershipID, glmURL) // model.Volume
+func GetVolumeResponseWithMountPath(ctx context.Context, resp *[]model.Volume,
+	transport *glm.Transport) (*[]model.Volume, error) {
+	capacityPools, err := capacity_pool.ListCapacityPool(ctx, transport) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
@@ -82,12 +85,10 @@ func GetVolumeResponseWithMountPath(resp *[]model.Volume, glmUsername string, gl
 			capacityPoolFound := false
 			for _, flavorId := range capacityPool.VolumeFlavors {
 				if volume.FlavorID == flavorId {
-					resp, err := capacity_pool.GetCapacityPool(glmUsername, glmPassword, glmSessionToken, membershipID,
-						glmURL, capacityPool.ID) // model.Volume
+					resp, err := capacity_pool.GetCapacityPool(ctx, transport, capacityPool.ID) // model.Volume
 					if err != nil {
-						msg := fmt.Sprintf("get capacitypool failed with error: %v", err)
-						log.Errorf(msg)
-						return nil, errors.New(msg)
+						log.Errorln(err)
+						return nil, err
 					}
 					log.Infof("CapacityPool cluster name: %v", resp.ClusterName)
 					mountedVolName := GetMountedVolumeName(volume)
@@ -3,15 +3,17 @@
 package volume
 
 import (
+	"context"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
 type ListVolumeHandler struct{}
 
 func (ch *ListVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
-	log.Infof("List volume args:%v\n", argsMap)
+	log.Infof("List volume args:%v\n", redact.Map(argsMap))
 	volume, err := model.NewVolume(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -30,29 +32,31 @@ func (ch *ListVolumeHandler) ValidateResource(operation string, volume *model.Vo
 	return nil
 }
 
-func (ch *ListVolumeHandler) Execute(volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+func (ch *ListVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
 	log.Infof("list volume:%v\n", volume)
-	resp, err := cli.ListVolumes()
+	resp, err := cli.ListVolumes(ctx)
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
-	sessionToken, err := model.GetSessionToken()
-	if err != nil {
-		log.Errorln(err)
-		return nil, err
-	}
-	glmCredentials, err := cli.GetGlmCredentials()
-	if err != nil {
-		log.Errorln(err)
-		return nil, err
+	// the selector was checked by MakeResource
+	selector, _ := model.ParseSelector(volume.Selector)
+	selected := []model.Volume{}
+	for _, item := range *resp {
+		if selector.Matches(item.Labels) {
+			selected = append(selected, item)
+		}
 	}
-	newResp, err := GetVolumeResponseWithMountPath(resp, glmCredentials["USER_NAME"], glmCredentials["PASSWORD"],
-		sessionToken, glmCredentials["MEMBERSHIP_ID"], glmCredentials["URL"])
+	// the mount path is only looked up for the volumes filtered in
+	selected = model.SelectVolumes(selected, volume.ListOptions.FilterOptions("mount_path"))
+	resp = &selected
+	newResp, err := GetVolumeResponseWithMountPath(ctx, resp, cli.GetTransport())
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
+	listed := model.SelectVolumes(*newResp, volume.ListOptions)
+	newResp = &listed
 	log.Infof("list volume response:%+v", newResp)
 	return newResp, nil
 }
@@ -0,0 +1,44 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package volume
+
+import (
+	"context"
+	client "github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type UpdateVolumeHandler struct{}
+
+func (ch *UpdateVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
+	log.Infof("Update volume args:%v\n", redact.Map(argsMap))
+	volume, err := model.NewVolume(operation, argsMap)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	return volume, nil
+}
+
+func (ch *UpdateVolumeHandler) ValidateResource(operation string, volume *model.Volume) error {
+	log.Infof("Validate update volume args:%v\n", volume)
+	err := ValidateVolumeRequest(operation, volume)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+func (ch *UpdateVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+	log.Infof("update volume:%v capacity:%v\n", volume.VolumeID, volume.Capacity)
+	resp, err := cli.UpdateVolume(ctx, volume) // model.Volume
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	log.Infof("update volume response:%+v", resp)
+	return resp, nil
+}
@@ -0,0 +1,44 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package volume
+
+import (
+	"context"
+	client "github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+)
+
+type WaitVolumeHandler struct{}
+
+func (ch *WaitVolumeHandler) MakeResource(operation string, argsMap map[string]interface{}) (*model.Volume, error) {
+	log.Infof("Wait volume args:%v\n", redact.Map(argsMap))
+	volume, err := model.NewVolume(operation, argsMap)
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	return volume, nil
+}
+
+func (ch *WaitVolumeHandler) ValidateResource(operation string, volume *model.Volume) error {
+	log.Infof("Validate wait volume args:%v\n", volume)
+	err := ValidateVolumeRequest(operation, volume)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+func (ch *WaitVolumeHandler) Execute(ctx context.Context, volume *model.Volume, cli client.ClientInterface) (interface{}, error) {
+	log.Infof("wait for volume:%v state:%v\n", volume.VolumeID, volume.State)
+	resp, err := cli.WaitForVolumeState(ctx, volume.VolumeID, volume.State) // model.Volume
+	if err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	log.Infof("wait volume response:%+v", resp)
+	return resp, nil
+}
@@ -3,11 +3,13 @@
 package ss
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	"github.com/hpe-hcss/lh-cdc-singularity/utils"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
@@ -15,7 +17,7 @@ import (
 type VolumeAttachmentHandler interface {
 	MakeResource(string, map[string]interface{}) (*model.VolumeAttachment, error)
 	ValidateResource(string, *model.VolumeAttachment) error
-	Execute(*model.VolumeAttachment, client.ClientInterface) (interface{}, error)
+	Execute(context.Context, *model.VolumeAttachment, client.ClientInterface) (interface{}, error)
 }
 
 type CmdHandlerVolumeAttachment struct {
@@ -25,8 +27,12 @@ type CmdHandlerVolumeAttachment struct {
 
 var supportedVolAttachmentOperations = []string{"create", "get", "delete", "list"}
 
+// newClient creates the GLM client of a volume attachment command; tests swap
+// in a mock.
+var newClient = client.NewClientFromConfig
+
 func NewCmdHandlerVolumeAttachment(args []string) *CmdHandlerVolumeAttachment {
-	log.Infof("NewCmdHandlerVolumeAttachment : %v", args)
+	log.Infof("NewCmdHandlerVolumeAttachment : %v", redact.Args(args))
 	ch := &CmdHandlerVolumeAttachment{}
 	opMap := map[string]VolumeAttachmentHandler{
 		constants.CREATE: &CreateVolumeAttachmentHandler{},
@@ -39,14 +45,14 @@ func NewCmdHandlerVolumeAttachment(args []string) *CmdHandlerVolumeAttachment {
 	return ch
 }
 
-func (ch *CmdHandlerVolumeAttachment) Handle(glmCredDetails map[string]string,
+func (ch *CmdHandlerVolumeAttachment) Handle(ctx context.Context, glmCredDetails map[string]string,
 	argsMap map[string]interface{}) (interface{}, error) {
 	log.Infof("Handle function")
 	var resp interface{}
 	operation := ch.args[0]
 	if err := utils.ValidateOperations(operation, supportedVolAttachmentOperations); err != nil {
 		log.Errorln(err)
-		return nil, err
+		return nil, model.ValidationError(err)
 	}
 
 	handler := ch.opMap[operation]
@@ -55,34 +61,41 @@ func (ch *CmdHandlerVolumeAttachment) Handle(glmCredDetails map[string]string,
 		volumeAttachment, err := handler.MakeResource(operation, argsMap)
 		if err != nil {
 			log.Errorln(err)
-			return nil, err
+			return nil, model.ValidationError(err)
 		}
 
 		err = handler.ValidateResource(operation, volumeAttachment)
 		if err != nil {
 			log.Errorln(err)
-			return nil, err
+			return nil, model.ValidationError(err)
+		}
+		waitOpts, err := utils.GetWaitOptions(argsMap)
+		if err != nil {
+			log.Errorln(err)
+			return nil, model.ValidationError(err)
 		}
 		glmUserName, glmPassword, err := utils.GetCredentials(argsMap)
+		if err != nil {
+			return nil, model.ValidationError(err)
+		}
+		cli, err := newClient(glmCredDetails, glmUserName, glmPassword)
 		if err != nil {
 			return nil, err
 		}
-		cli := client.NewClient(glmCredDetails[constants.GLM_PORTAL], glmUserName,
-			glmPassword, glmCredDetails[constants.MEMBERSHIP_ID])
-		resp, err = handler.Execute(volumeAttachment, cli)
-		if err == model.TokenError || err == client.UndefinedResponseError || err == client.TokenExpiredError {
+		cli.SetWaitOptions(waitOpts)
+		resp, err = handler.Execute(ctx, volumeAttachment, cli)
+		if client.IsSessionError(err) {
 			log.Errorf("Execute err %+v", err)
 			//loginRequired = true
-			//cli.Login()
-			err := cli.Login()
+			//cli.Login(ctx)
+			err := cli.Login(ctx)
 			if err != nil {
-				msg := fmt.Sprintf("Session creation failed with error: %v", err)
-				log.Errorf(msg)
-				return nil, errors.New(msg)
+				err = fmt.Errorf("Session creation failed with error: %w", err)
+				log.Errorln(err)
+				return nil, err
 			}
-			defer cli.Logout()
 			//resp, err = handler.Execute(volume, glmCredDetails, glmUserName, glmPassword, loginRequired)
-			resp, err = handler.Execute(volumeAttachment, cli)
+			resp, err = handler.Execute(ctx, volumeAttachment, cli)
 			if err != nil {
 				log.Errorln(err)
 				return nil, err
@@ -94,7 +107,7 @@ func (ch *CmdHandlerVolumeAttachment) Handle(glmCredDetails map[string]string,
 	} else {
 		msg := fmt.Sprintf("unsupported sub-command: %s", operation)
 		log.Errorln(msg)
-		return nil, errors.New(msg)
+		return nil, model.ValidationError(errors.New(msg))
 	}
 	return resp, nil
 }
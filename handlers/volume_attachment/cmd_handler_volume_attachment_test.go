@@ -0,0 +1,209 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package ss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockclient"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockvolumeattachment"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testVolumeID     = "5fb4a4a5-7a1c-4d4e-9a3c-2f0f1b0b6a01"
+	testAttachmentID = "0b6c2a51-93d4-4f2e-8d1a-7c3e5f9a2b02"
+)
+
+func TestVolumeAttachmentHandlers(t *testing.T) {
+	ready := &model.VolumeAttachment{Name: "att1", VolumeID: testVolumeID, AttachmentID: testAttachmentID,
+		State: glmClient.VASTATEENUM_READY, FSConfig: &glmClient.VafsConfig{StorageID: "cluster1"}}
+	deleted := &model.VolumeAttachment{AttachmentID: testAttachmentID, State: glmClient.VASTATEENUM_DELETED}
+
+	tests := []struct {
+		name      string
+		args      []string
+		expect    func(cli *mockclient.MockClientInterface)
+		want      interface{}
+		wantError string
+	}{
+		{name: "create",
+			args: []string{"create", "name=att1", "volume_id=" + testVolumeID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().CreateVolumeAttachment(gomock.Any(),
+					&model.VolumeAttachment{Name: "att1", VolumeID: testVolumeID}).Return(ready, nil)
+			},
+			want: ready},
+		{name: "create fails",
+			args: []string{"create", "name=att1", "volume_id=" + testVolumeID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().CreateVolumeAttachment(gomock.Any(), gomock.Any()).Return(nil,
+					model.NewError(model.ErrorConflict, errors.New("volume is attached already")))
+			},
+			wantError: "volume is attached already"},
+		{name: "create without name",
+			args:      []string{"create", "name=", "volume_id=" + testVolumeID},
+			wantError: "invalid value of volume attachment name is provided"},
+		{name: "create without volume id",
+			args:      []string{"create", "name=att1"},
+			wantError: "Argument 'volume_id' is missing"},
+		{name: "get",
+			args: []string{"get", "attachment_id=" + testAttachmentID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().GetVolumeAttachment(gomock.Any(), testAttachmentID).Return(ready, nil)
+			},
+			want: ready},
+		{name: "get with volume id",
+			args:      []string{"get", "attachment_id=" + testAttachmentID, "volume_id=" + testVolumeID},
+			wantError: "Invalid argument volume_id"},
+		{name: "delete",
+			args: []string{"delete", "attachment_id=" + testAttachmentID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().DeleteVolumeAttachment(gomock.Any(), testAttachmentID).Return(deleted, nil)
+			},
+			want: deleted},
+		{name: "delete without attachment id",
+			args:      []string{"delete", "attachment_id="},
+			wantError: "volume attachment_id is not provided"},
+		{name: "list",
+			args: []string{"list"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeAttachments(gomock.Any()).Return(&[]model.VolumeAttachment{*ready}, nil)
+			},
+			want: &[]model.VolumeAttachment{*ready}},
+		{name: "list with selector",
+			args: []string{"list", "selector=team=genomics"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				other := model.VolumeAttachment{Name: "att2", VolumeID: "other", AttachmentID: "other"}
+				cli.EXPECT().ListVolumeAttachments(gomock.Any()).Return(
+					&[]model.VolumeAttachment{*ready, other}, nil)
+				cli.EXPECT().ListVolumes(gomock.Any()).Return(&[]model.Volume{
+					{VolumeID: testVolumeID, Labels: model.Labels{"team": "genomics"}},
+					{VolumeID: "other", Labels: model.Labels{"team": "physics"}}}, nil)
+			},
+			want: &[]model.VolumeAttachment{*ready}},
+		{name: "list filtered by volume",
+			args: []string{"list", "filter=volume_id=" + testVolumeID},
+			expect: func(cli *mockclient.MockClientInterface) {
+				other := model.VolumeAttachment{Name: "att2", VolumeID: "other", AttachmentID: "other"}
+				cli.EXPECT().ListVolumeAttachments(gomock.Any()).Return(
+					&[]model.VolumeAttachment{other, *ready}, nil)
+			},
+			want: &[]model.VolumeAttachment{*ready}},
+		{name: "list with invalid selector",
+			args:      []string{"list", "selector=team=genomics,"},
+			wantError: "invalid value of selector is provided"},
+		{name: "list with name",
+			args:      []string{"list", "name=att1"},
+			wantError: "Invalid argument name"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			if tc.expect != nil {
+				tc.expect(cli)
+			}
+			argsMap, err := utils.MakeCommand(tc.args[1:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			operation := tc.args[0]
+			handler := NewCmdHandlerVolumeAttachment(tc.args).opMap[operation]
+
+			resp, err := func() (interface{}, error) {
+				attachment, err := handler.MakeResource(operation, argsMap)
+				if err != nil {
+					return nil, err
+				}
+				if err := handler.ValidateResource(operation, attachment); err != nil {
+					return nil, err
+				}
+				return handler.Execute(context.Background(), attachment, cli)
+			}()
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("got %+v, %v, want error %q", resp, err, tc.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resp, tc.want) {
+				t.Errorf("got %+v, want %+v", resp, tc.want)
+			}
+		})
+	}
+}
+
+func TestCmdHandlerVolumeAttachmentLogsInAgain(t *testing.T) {
+	attachment := &model.VolumeAttachment{AttachmentID: testAttachmentID}
+	expired := model.NewError(model.ErrorUnauthorized,
+		fmt.Errorf("get volume attachment failed: %w", glm.TokenExpiredError))
+
+	tests := []struct {
+		name  string
+		first error
+		login error
+		// retry is the error of Execute after logging in again
+		retry     error
+		wantError string
+	}{
+		{name: "no cached session", first: model.TokenError},
+		{name: "session expired", first: expired},
+		{name: "login fails", first: expired, login: model.NewError(model.ErrorUnauthorized,
+			errors.New("invalid credentials")), wantError: "Session creation failed with error: invalid credentials"},
+		{name: "retry fails", first: model.TokenError, retry: model.TokenError, wantError: model.TokenError.Error()},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			handler := mockvolumeattachment.NewMockVolumeAttachmentHandler(ctrl)
+			saved := newClient
+			newClient = func(map[string]string, string, string) (client.ClientInterface, error) {
+				return cli, nil
+			}
+			defer func() { newClient = saved }()
+
+			handler.EXPECT().MakeResource(constants.GET, gomock.Any()).Return(attachment, nil)
+			handler.EXPECT().ValidateResource(constants.GET, attachment).Return(nil)
+			cli.EXPECT().SetWaitOptions(gomock.Any())
+			calls := []*gomock.Call{
+				handler.EXPECT().Execute(gomock.Any(), attachment, cli).Return(nil, tc.first),
+				cli.EXPECT().Login(gomock.Any()).Return(tc.login),
+			}
+			if tc.login == nil {
+				var resp interface{}
+				if tc.retry == nil {
+					resp = attachment
+				}
+				calls = append(calls, handler.EXPECT().Execute(gomock.Any(), attachment, cli).Return(resp, tc.retry))
+			}
+			gomock.InOrder(calls...)
+
+			ch := &CmdHandlerVolumeAttachment{args: []string{constants.GET},
+				opMap: map[string]VolumeAttachmentHandler{constants.GET: handler}}
+			resp, err := ch.Handle(context.Background(), map[string]string{}, map[string]interface{}{
+				constants.USERNAME: "user@hpe.com", constants.PASSWORD: "secret"})
+			if tc.wantError == "" {
+				if err != nil || resp != attachment {
+					t.Errorf("got %+v, %v", resp, err)
+				}
+			} else if err == nil || err.Error() != tc.wantError {
+				t.Errorf("got %v, want %s", err, tc.wantError)
+			}
+		})
+	}
+}
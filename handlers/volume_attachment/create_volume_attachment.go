@@ -3,8 +3,10 @@
 package ss
 
 import (
+	"context"
 	client "github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -12,7 +14,7 @@ type CreateVolumeAttachmentHandler struct{}
 
 func (ch *CreateVolumeAttachmentHandler) MakeResource(operation string,
 	argsMap map[string]interface{}) (*model.VolumeAttachment, error) {
-	log.Infof("Create volume attachment args:%v\n", argsMap)
+	log.Infof("Create volume attachment args:%v\n", redact.Map(argsMap))
 	volumeAttachment, err := model.MakeVolumeAttachment(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -32,14 +34,14 @@ func (ch *CreateVolumeAttachmentHandler) ValidateResource(operation string,
 	return nil
 }
 
-func (ch *CreateVolumeAttachmentHandler) Execute(volumeAttachment *model.VolumeAttachment,
+func (ch *CreateVolumeAttachmentHandler) Execute(ctx context.Context, volumeAttachment *model.VolumeAttachment,
 	cli client.ClientInterface) (interface{}, error) {
 	log.Infof("create volume attachment:%v\n", volumeAttachment)
-	resp, err := cli.CreateVolumeAttachment(volumeAttachment) // model.Volume
+	resp, err := cli.CreateVolumeAttachment(ctx, volumeAttachment) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("create volume attachment response:%+v", resp)
+	log.Infof("create volume attachment response:%+v", redact.Value(resp))
 	return resp, nil
 }
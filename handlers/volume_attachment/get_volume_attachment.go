@@ -3,8 +3,10 @@
 package ss
 
 import (
+	"context"
 	"github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -12,7 +14,7 @@ type GetVolumeAttachmentHandler struct{}
 
 func (ch *GetVolumeAttachmentHandler) MakeResource(operation string,
 	argsMap map[string]interface{}) (*model.VolumeAttachment, error) {
-	log.Infof("Get volume attachment args:%v\n", argsMap)
+	log.Infof("Get volume attachment args:%v\n", redact.Map(argsMap))
 	volumeAttachment, err := model.MakeVolumeAttachment(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -32,14 +34,14 @@ func (ch *GetVolumeAttachmentHandler) ValidateResource(operation string,
 	return nil
 }
 
-func (ch *GetVolumeAttachmentHandler) Execute(volumeAttachment *model.VolumeAttachment,
+func (ch *GetVolumeAttachmentHandler) Execute(ctx context.Context, volumeAttachment *model.VolumeAttachment,
 	cli client.ClientInterface) (interface{}, error) {
 	log.Infof("get volume attachment :%v\n", volumeAttachment.AttachmentID)
-	resp, err := cli.GetVolumeAttachment(volumeAttachment.AttachmentID) // model.Volume
+	resp, err := cli.GetVolumeAttachment(ctx, volumeAttachment.AttachmentID) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("get volume attachment response:%+v", resp)
+	log.Infof("get volume attachment response:%+v", redact.Value(resp))
 	return resp, nil
 }
@@ -3,8 +3,10 @@
 package ss
 
 import (
+	"context"
 	"github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -12,7 +14,7 @@ type ListVolumeAttachmentHandler struct{}
 
 func (ch *ListVolumeAttachmentHandler) MakeResource(operation string,
 	argsMap map[string]interface{}) (*model.VolumeAttachment, error) {
-	log.Infof("list volume attachment args:%v\n", argsMap)
+	log.Infof("list volume attachment args:%v\n", redact.Map(argsMap))
 	volumeAttachment, err := model.MakeVolumeAttachment(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -32,14 +34,48 @@ func (ch *ListVolumeAttachmentHandler) ValidateResource(operation string,
 	return nil
 }
 
-func (ch *ListVolumeAttachmentHandler) Execute(volumeAttachment *model.VolumeAttachment,
+// selectVolumeAttachments returns the attachments of the volumes whose labels
+// match selector. Attachments have no labels of their own.
+func selectVolumeAttachments(ctx context.Context, attachments *[]model.VolumeAttachment, selector string,
+	cli client.ClientInterface) (*[]model.VolumeAttachment, error) {
+	// the selector was checked by MakeResource
+	sel, _ := model.ParseSelector(selector)
+	volumes, err := cli.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selectedVolumes := map[string]bool{}
+	for _, volume := range *volumes {
+		if sel.Matches(volume.Labels) {
+			selectedVolumes[volume.VolumeID] = true
+		}
+	}
+	selected := []model.VolumeAttachment{}
+	for _, attachment := range *attachments {
+		if selectedVolumes[attachment.VolumeID] {
+			selected = append(selected, attachment)
+		}
+	}
+	return &selected, nil
+}
+
+func (ch *ListVolumeAttachmentHandler) Execute(ctx context.Context, volumeAttachment *model.VolumeAttachment,
 	cli client.ClientInterface) (interface{}, error) {
 	log.Infof("list volume attachments :%v\n", volumeAttachment.AttachmentID)
-	resp, err := cli.ListVolumeAttachments() // model.Volume
+	resp, err := cli.ListVolumeAttachments(ctx) // model.Volume
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
-	log.Infof("list volume attachments response:%+v", resp)
+	if volumeAttachment.Selector != "" {
+		resp, err = selectVolumeAttachments(ctx, resp, volumeAttachment.Selector, cli)
+		if err != nil {
+			log.Errorln(err)
+			return nil, err
+		}
+	}
+	selected := model.SelectVolumeAttachments(*resp, volumeAttachment.ListOptions)
+	resp = &selected
+	log.Infof("list volume attachments response:%+v", redact.Value(resp))
 	return resp, nil
 }
@@ -3,11 +3,13 @@
 package volume_flavors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	"github.com/hpe-hcss/lh-cdc-singularity/utils"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
@@ -15,7 +17,7 @@ import (
 type VolumeFlavorHandler interface {
 	MakeResource(string, map[string]interface{}) (*model.VolumeFlavor, error)
 	ValidateResource(string, *model.VolumeFlavor) error
-	Execute(*model.VolumeFlavor, client.ClientInterface) (interface{}, error)
+	Execute(context.Context, *model.VolumeFlavor, client.ClientInterface) (interface{}, error)
 }
 
 type CmdHandlerVolumeFlavor struct {
@@ -25,8 +27,11 @@ type CmdHandlerVolumeFlavor struct {
 
 var supportedVolFlavorOperations = []string{"list"}
 
+// newClient is replaced by tests that list flavors with a mock client.
+var newClient = client.NewClientFromConfig
+
 func NewCmdHandlerVolumeFlavor(args []string) *CmdHandlerVolumeFlavor {
-	log.Infof("NewCmdHandlerVolumeFlavor : %v", args)
+	log.Infof("NewCmdHandlerVolumeFlavor : %v", redact.Args(args))
 	return &CmdHandlerVolumeFlavor{
 		opMap: map[string]VolumeFlavorHandler{
 			"list": &ListVolumeFlavorHandler{},
@@ -35,54 +40,55 @@ func NewCmdHandlerVolumeFlavor(args []string) *CmdHandlerVolumeFlavor {
 	}
 }
 
-func (ch *CmdHandlerVolumeFlavor) Handle(glmCredDetails map[string]string,
+func (ch *CmdHandlerVolumeFlavor) Handle(ctx context.Context, glmCredDetails map[string]string,
 	argsMap map[string]interface{}) (interface{}, error) {
 	log.Infof("Handle function")
 	var resp interface{}
 	operation := ch.args[0]
 	if err := utils.ValidateOperations(operation, supportedVolFlavorOperations); err != nil {
 		log.Errorln(err)
-		return nil, err
+		return nil, model.ValidationError(err)
 	}
 
 	handler := ch.opMap[operation]
 	if handler == nil {
 		msg := fmt.Sprintf("unsupported sub-command: %s", operation)
 		log.Errorln(msg)
-		return nil, errors.New(msg)
+		return nil, model.ValidationError(errors.New(msg))
 	}
 	//validating parameters
 	volumeFlavor, err := handler.MakeResource(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
-		return nil, err
+		return nil, model.ValidationError(err)
 	}
 
 	if err := handler.ValidateResource(operation, volumeFlavor); err != nil {
 		log.Errorln(err)
-		return nil, err
+		return nil, model.ValidationError(err)
 	}
 
 	glmUserName, glmPassword, err := utils.GetCredentials(argsMap)
+	if err != nil {
+		return nil, model.ValidationError(err)
+	}
+	cli, err := newClient(glmCredDetails, glmUserName, glmPassword)
 	if err != nil {
 		return nil, err
 	}
-	cli := client.NewClient(glmCredDetails[constants.GLM_PORTAL], glmUserName,
-		glmPassword, glmCredDetails[constants.MEMBERSHIP_ID])
-	resp, err = handler.Execute(volumeFlavor, cli)
-	if err == model.TokenError || err == client.UndefinedResponseError || err == client.TokenExpiredError {
+	resp, err = handler.Execute(ctx, volumeFlavor, cli)
+	if client.IsSessionError(err) {
 		log.Errorf("Execute err %+v", err)
 		//loginRequired = true
-		//cli.Login()
-		err := cli.Login()
+		//cli.Login(ctx)
+		err := cli.Login(ctx)
 		if err != nil {
-			msg := fmt.Sprintf("Session creation failed with error: %v", err)
-			log.Errorf(msg)
-			return nil, errors.New(msg)
+			err = fmt.Errorf("Session creation failed with error: %w", err)
+			log.Errorln(err)
+			return nil, err
 		}
-		defer cli.Logout()
 		//resp, err = handler.Execute(volume, glmCredDetails, glmUserName, glmPassword, loginRequired)
-		resp, err = handler.Execute(volumeFlavor, cli)
+		resp, err = handler.Execute(ctx, volumeFlavor, cli)
 		if err != nil {
 			log.Errorln(err)
 			return nil, err
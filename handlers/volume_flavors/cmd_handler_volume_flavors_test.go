@@ -0,0 +1,155 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package volume_flavors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockclient"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockvolumeflavors"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testFlavors = &[]model.VolumeFlavor{{ID: "0344e238-6a4f-4f0c-9a0e-5a6a2b1c3d4e", Name: "Default"},
+	{ID: "7e1b9c42-3f5d-4a8e-b6c1-2d9f0a7e8b53", Name: "Fast"}}
+
+func TestVolumeFlavorHandlers(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expect    func(cli *mockclient.MockClientInterface)
+		want      interface{}
+		wantError string
+	}{
+		{name: "list",
+			args: []string{"list"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(testFlavors, nil)
+			},
+			want: testFlavors},
+		{name: "list fails",
+			args: []string{"list"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(nil,
+					model.NewError(model.ErrorTransport, errors.New("connection refused")))
+			},
+			wantError: "connection refused"},
+		{name: "list sorted",
+			args: []string{"list", "sort=name:desc", "limit=1"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(testFlavors, nil)
+			},
+			want: &[]model.VolumeFlavor{(*testFlavors)[1]}},
+		{name: "list filtered",
+			args: []string{"list", "filter=name=D*"},
+			expect: func(cli *mockclient.MockClientInterface) {
+				cli.EXPECT().ListVolumeFlavors(gomock.Any()).Return(testFlavors, nil)
+			},
+			want: &[]model.VolumeFlavor{(*testFlavors)[0]}},
+		{name: "list sorted by unknown field",
+			args:      []string{"list", "sort=capacity"},
+			wantError: `invalid value of sort is provided: unknown field "capacity", fields are id, name`},
+		{name: "list by name",
+			args:      []string{"list", "flavor_name=Fast"},
+			wantError: "Invalid argument flavor_name"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			if tc.expect != nil {
+				tc.expect(cli)
+			}
+			argsMap, err := utils.MakeCommand(tc.args[1:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			operation := tc.args[0]
+			handler := NewCmdHandlerVolumeFlavor(tc.args).opMap[operation]
+
+			resp, err := func() (interface{}, error) {
+				volumeFlavor, err := handler.MakeResource(operation, argsMap)
+				if err != nil {
+					return nil, err
+				}
+				if err := handler.ValidateResource(operation, volumeFlavor); err != nil {
+					return nil, err
+				}
+				return handler.Execute(context.Background(), volumeFlavor, cli)
+			}()
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("got %+v, %v, want error %q", resp, err, tc.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resp, tc.want) {
+				t.Errorf("got %+v, want %+v", resp, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateListVolumeFlavorRequest(t *testing.T) {
+	for _, volumeFlavor := range []*model.VolumeFlavor{{ID: "f1"}, {Name: "Fast"}} {
+		if err := validateVolumeFlavorRequest(constants.LIST, volumeFlavor); err == nil {
+			t.Errorf("%+v: got no error", volumeFlavor)
+		}
+	}
+}
+
+func TestGetVolumeFlavorID(t *testing.T) {
+	if id, err := GetVolumeFlavorID("Fast", testFlavors); err != nil || id != (*testFlavors)[1].ID {
+		t.Errorf("got %s, %v", id, err)
+	}
+	if _, err := GetVolumeFlavorID("Slow", testFlavors); err == nil {
+		t.Error("unknown flavor: got no error")
+	}
+}
+
+func TestCmdHandlerVolumeFlavorLogsInAgain(t *testing.T) {
+	expired := model.NewError(model.ErrorUnauthorized,
+		fmt.Errorf("list volume flavors failed: %w", glm.TokenExpiredError))
+
+	for _, first := range []error{model.TokenError, expired} {
+		t.Run(first.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cli := mockclient.NewMockClientInterface(ctrl)
+			handler := mockvolumeflavors.NewMockVolumeFlavorHandler(ctrl)
+			saved := newClient
+			newClient = func(map[string]string, string, string) (client.ClientInterface, error) {
+				return cli, nil
+			}
+			defer func() { newClient = saved }()
+
+			volumeFlavor := &model.VolumeFlavor{}
+			handler.EXPECT().MakeResource(constants.LIST, gomock.Any()).Return(volumeFlavor, nil)
+			handler.EXPECT().ValidateResource(constants.LIST, volumeFlavor).Return(nil)
+			gomock.InOrder(
+				handler.EXPECT().Execute(gomock.Any(), volumeFlavor, cli).Return(nil, first),
+				cli.EXPECT().Login(gomock.Any()).Return(nil),
+				handler.EXPECT().Execute(gomock.Any(), volumeFlavor, cli).Return(testFlavors, nil),
+			)
+
+			ch := &CmdHandlerVolumeFlavor{args: []string{constants.LIST},
+				opMap: map[string]VolumeFlavorHandler{constants.LIST: handler}}
+			resp, err := ch.Handle(context.Background(), map[string]string{}, map[string]interface{}{
+				constants.USERNAME: "user@hpe.com", constants.PASSWORD: "secret"})
+			if err != nil || resp != testFlavors {
+				t.Errorf("got %+v, %v", resp, err)
+			}
+		})
+	}
+}
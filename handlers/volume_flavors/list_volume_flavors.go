@@ -3,9 +3,11 @@
 package volume_flavors
 
 import (
+	"context"
 	"errors"
 	"github.com/hpe-hcss/lh-cdc-singularity/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -13,7 +15,7 @@ type ListVolumeFlavorHandler struct{}
 
 func (ch *ListVolumeFlavorHandler) MakeResource(operation string,
 	argsMap map[string]interface{}) (*model.VolumeFlavor, error) {
-	log.Infof("list volume flavors args:%v\n", argsMap)
+	log.Infof("list volume flavors args:%v\n", redact.Map(argsMap))
 	volumeFlavor, err := model.MakeVolumeFlavor(operation, argsMap)
 	if err != nil {
 		log.Errorln(err)
@@ -32,14 +34,16 @@ func (ch *ListVolumeFlavorHandler) ValidateResource(operation string,
 	return nil
 }
 
-func (ch *ListVolumeFlavorHandler) Execute(volumeFlavor *model.VolumeFlavor,
+func (ch *ListVolumeFlavorHandler) Execute(ctx context.Context, volumeFlavor *model.VolumeFlavor,
 	cli client.ClientInterface) (interface{}, error) {
 	log.Infof("list volume flavors :%v\n", volumeFlavor.ID)
-	resp, err := cli.ListVolumeFlavors()
+	resp, err := cli.ListVolumeFlavors(ctx)
 	if err != nil {
 		log.Errorln(err)
 		return nil, err
 	}
+	selected := model.SelectVolumeFlavors(*resp, volumeFlavor.ListOptions)
+	resp = &selected
 	log.Infof("list volume flavors response:%+v", resp)
 	return resp, nil
 }
@@ -0,0 +1,73 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"errors"
+	"fmt"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"net/http"
+)
+
+var UndefinedResponseError = errors.New("undefined response type")
+var TokenExpiredError = errors.New("Token is expired")
+
+// TransportError describes a request that got no response from GLM.
+func TransportError(operation string, err error) error {
+	return &model.APIError{
+		Category: model.ErrorTransport,
+		Message:  fmt.Sprintf("%s failed: %v", operation, err),
+		Err:      err,
+	}
+}
+
+// StatusError describes a request GLM answered with an error status. GLM
+// rejects expired tokens with a plain text body; those errors wrap
+// TokenExpiredError so that the handlers log in again.
+func StatusError(operation string, resp *http.Response, body []byte) *model.APIError {
+	apiErr := model.NewStatusError(operation, resp.StatusCode, body)
+	apiErr.RequestID = resp.Header.Get(constants.REQUEST_ID_HEADER)
+	if resp.StatusCode == http.StatusUnauthorized || model.ServerMessage(body) == TokenExpiredError.Error() {
+		apiErr.Err = TokenExpiredError
+	}
+	return apiErr
+}
+
+// APIClientError converts an error of the GLM API client into a
+// *model.APIError carrying the HTTP status and the server message. GLM answers
+// some requests, such as those with an expired token, in plain text, which the
+// API client cannot decode; those errors also wrap UndefinedResponseError or
+// TokenExpiredError.
+func APIClientError(operation string, httpResp *http.Response, err error) error {
+	var apiErr *model.APIError
+	if httpResp == nil && errors.As(err, &apiErr) {
+		// the transport failed before sending the request, e.g. without a session
+		return apiErr
+	}
+	if httpResp == nil && errors.Is(err, model.TokenError) {
+		return model.TokenError
+	}
+	var openAPIErr glmClient.GenericOpenAPIError
+	if httpResp == nil || !errors.As(err, &openAPIErr) {
+		return TransportError(operation, err)
+	}
+	if httpResp.StatusCode < http.StatusMultipleChoices {
+		apiErr = &model.APIError{
+			Category:   model.ErrorInternal,
+			StatusCode: httpResp.StatusCode,
+			Message:    fmt.Sprintf("%s failed: unexpected response: %v", operation, err),
+			RequestID:  httpResp.Header.Get(constants.REQUEST_ID_HEADER),
+		}
+		if err.Error() == UndefinedResponseError.Error() {
+			apiErr.Err = UndefinedResponseError
+		}
+		return apiErr
+	}
+	statusErr := StatusError(operation, httpResp, openAPIErr.Body())
+	if err.Error() == UndefinedResponseError.Error() && statusErr.Err == nil {
+		statusErr.Err = UndefinedResponseError
+	}
+	return statusErr
+}
@@ -0,0 +1,44 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"crypto/tls"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns the HTTP client a command uses for all its requests to
+// the GLM portal. Connections are kept alive and reused, so that a command
+// making several requests does a single TLS handshake, and HTTP/2 is used when
+// the portal offers it. Requests go through the proxy chosen by proxy, and
+// headers are added to each of them. Each exchange is recorded by tracer,
+// unless it is nil. The overall duration of a request is
+// bounded by its context, not by the client.
+func NewHTTPClient(tlsConfig *tls.Config, retryPolicy RetryPolicy, proxy ProxyFunc, headers http.Header,
+	tracer *Tracer) *http.Client {
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   constants.HTTP_DIAL_TIMEOUT * time.Second,
+			KeepAlive: constants.HTTP_KEEP_ALIVE * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   constants.HTTP_TLS_HANDSHAKE_TIMEOUT * time.Second,
+		ResponseHeaderTimeout: constants.HTTP_RESPONSE_HEADER_TIMEOUT * time.Second,
+		IdleConnTimeout:       constants.HTTP_IDLE_CONN_TIMEOUT * time.Second,
+		MaxIdleConnsPerHost:   constants.HTTP_MAX_IDLE_CONNS_PER_HOST,
+		ExpectContinueTimeout: time.Second,
+	}
+	var base http.RoundTripper = transport
+	if tracer != nil {
+		base = &traceTransport{base: base, tracer: tracer}
+	}
+	if len(headers) > 0 {
+		base = &headerTransport{base: base, headers: headers}
+	}
+	return &http.Client{Transport: NewRetryTransport(base, retryPolicy)}
+}
@@ -0,0 +1,91 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"golang.org/x/net/http/httpproxy"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFunc chooses the proxy of a request, nil meaning a direct connection.
+type ProxyFunc func(req *http.Request) (*url.URL, error)
+
+// NewProxyFunc returns the proxy selection for GLM requests. It starts from
+// the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables. The proxy
+// and no_proxy keys of the [glm_credentials] section of plugin.conf override
+// them, and proxy_username and proxy_password authenticate with the proxy
+// when its URL carries no credentials.
+func NewProxyFunc(glmCredDetails map[string]string) (ProxyFunc, error) {
+	config := httpproxy.FromEnvironment()
+	if proxy := glmCredDetails[constants.PROXY]; proxy != "" {
+		if _, err := url.Parse(proxy); err != nil {
+			return nil, fmt.Errorf("invalid value of %s in plugin.conf: %v", constants.PROXY, err)
+		}
+		config.HTTPProxy = proxy
+		config.HTTPSProxy = proxy
+	}
+	if noProxy, ok := glmCredDetails[constants.NO_PROXY]; ok {
+		config.NoProxy = noProxy
+	}
+
+	username := glmCredDetails[constants.PROXY_USERNAME]
+	password := glmCredDetails[constants.PROXY_PASSWORD]
+	if username == "" && password != "" {
+		return nil, fmt.Errorf("%s is set without %s in plugin.conf", constants.PROXY_PASSWORD,
+			constants.PROXY_USERNAME)
+	}
+
+	proxyForURL := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		proxy, err := proxyForURL(req.URL)
+		if err != nil || proxy == nil || username == "" || proxy.User != nil {
+			return proxy, err
+		}
+		withAuth := *proxy
+		withAuth.User = url.UserPassword(username, password)
+		return &withAuth, nil
+	}, nil
+}
+
+// NewExtraHeaders parses the extra_headers key of the [glm_credentials]
+// section of plugin.conf: "Name: value" pairs separated by semicolons, e.g.
+// "X-Site-Route: dc1; X-Team: storage". The headers are sent with every GLM
+// request.
+func NewExtraHeaders(glmCredDetails map[string]string) (http.Header, error) {
+	headers := http.Header{}
+	for _, entry := range strings.Split(glmCredDetails[constants.EXTRA_HEADERS], ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tokens := strings.SplitN(entry, ":", 2)
+		name := strings.TrimSpace(tokens[0])
+		if len(tokens) != 2 || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header '%s' in %s of plugin.conf, expected 'Name: value'",
+				entry, constants.EXTRA_HEADERS)
+		}
+		headers.Add(name, strings.TrimSpace(tokens[1]))
+	}
+	return headers, nil
+}
+
+// headerTransport adds the configured extra headers to every request. Headers
+// the request sets itself, such as Authorization, are left alone.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	for name, values := range t.headers {
+		if _, ok := clone.Header[name]; !ok {
+			clone.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(clone)
+}
@@ -0,0 +1,191 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient failures of GLM requests are retried:
+// network errors, 5xx responses and 429 Too Many Requests.
+type RetryPolicy struct {
+	MaxRetries   int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:   constants.DEFAULT_MAX_RETRIES,
+		InitialDelay: constants.DEFAULT_RETRY_INITIAL_DELAY * time.Millisecond,
+		MaxDelay:     constants.DEFAULT_RETRY_MAX_DELAY * time.Second,
+	}
+}
+
+// NewRetryPolicy reads the retry policy from the [glm_credentials] section of
+// plugin.conf. max_retries=0 turns retries off. The delays are Go durations
+// such as "500ms" or a number of seconds.
+func NewRetryPolicy(glmCredDetails map[string]string) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+	if val := glmCredDetails[constants.MAX_RETRIES]; val != "" {
+		retries, err := strconv.Atoi(val)
+		if err != nil || retries < 0 {
+			return policy, fmt.Errorf("invalid value '%s' of %s in plugin.conf", val, constants.MAX_RETRIES)
+		}
+		policy.MaxRetries = retries
+	}
+	for key, delay := range map[string]*time.Duration{
+		constants.RETRY_INITIAL_DELAY: &policy.InitialDelay,
+		constants.RETRY_MAX_DELAY:     &policy.MaxDelay,
+	} {
+		val := glmCredDetails[key]
+		if val == "" {
+			continue
+		}
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			seconds, convErr := strconv.Atoi(val)
+			if convErr != nil {
+				return policy, fmt.Errorf("invalid value '%s' of %s in plugin.conf", val, key)
+			}
+			d = time.Duration(seconds) * time.Second
+		}
+		if d <= 0 {
+			return policy, fmt.Errorf("invalid value '%s' of %s in plugin.conf", val, key)
+		}
+		*delay = d
+	}
+	if policy.MaxDelay < policy.InitialDelay {
+		policy.MaxDelay = policy.InitialDelay
+	}
+	return policy, nil
+}
+
+// backoff returns the delay before retry attempt (counted from 0): an
+// exponentially growing delay with full jitter over its upper half, so that
+// commands started together do not retry in lockstep.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	d := policy.InitialDelay
+	for i := 0; i < attempt && d < policy.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// RetryTransport is an http.RoundTripper retrying idempotent requests that
+// failed with a network error, a 5xx status or 429 Too Many Requests. A
+// Retry-After header of the response is honoured.
+type RetryTransport struct {
+	Base   http.RoundTripper
+	Policy RetryPolicy
+}
+
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy) *RetryTransport {
+	return &RetryTransport{Base: base, Policy: policy}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.Base.RoundTrip(req)
+	}
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if attempt >= t.Policy.MaxRetries || !isTransientFailure(req.Context(), resp, err) {
+			return resp, err
+		}
+		delay := t.Policy.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			if retryAfter > constants.MAX_RETRY_AFTER*time.Second {
+				// the portal will not be back while the command runs
+				return resp, err
+			}
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+		if err != nil {
+			log.Warnf("%s %s failed: %v, retrying in %v", req.Method, req.URL.Path, err, delay)
+		} else {
+			log.Warnf("%s %s returned %s, retrying in %v", req.Method, req.URL.Path, resp.Status, delay)
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable reports whether req may be sent again: its method must be
+// idempotent and its body, if any, must be replayable.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func isTransientFailure(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented)
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// parseRetryAfter reads the Retry-After header, given in seconds or as an
+// HTTP date.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	val := resp.Header.Get("Retry-After")
+	if val == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(val); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
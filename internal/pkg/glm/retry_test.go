@@ -0,0 +1,163 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// faultServer fails the first failures requests with fail and answers the
+// rest with 200.
+func faultServer(t *testing.T, failures int32, fail http.HandlerFunc) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			fail(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func failWith(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(code) }
+}
+
+func send(t *testing.T, policy RetryPolicy, method, url string) (*http.Response, error) {
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy)}
+	req, err := http.NewRequest(method, url, strings.NewReader(`{"name":"vol"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		_, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestRetryTransport(t *testing.T) {
+	closeConn := func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}
+	tests := []struct {
+		name       string
+		method     string
+		failures   int32
+		fail       http.HandlerFunc
+		wantStatus int
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{"503 recovers", http.MethodGet, 2, failWith(http.StatusServiceUnavailable), http.StatusOK, 3, false},
+		{"502 recovers", http.MethodDelete, 1, failWith(http.StatusBadGateway), http.StatusOK, 2, false},
+		{"429 recovers", http.MethodGet, 1, failWith(http.StatusTooManyRequests), http.StatusOK, 2, false},
+		{"connection reset recovers", http.MethodGet, 2, closeConn, http.StatusOK, 3, false},
+		{"PUT body is replayed", http.MethodPut, 1, failWith(http.StatusInternalServerError), http.StatusOK, 2, false},
+		{"retries exhausted", http.MethodGet, 10, failWith(http.StatusServiceUnavailable), http.StatusServiceUnavailable, 4, false},
+		{"POST is not retried", http.MethodPost, 1, failWith(http.StatusServiceUnavailable), http.StatusServiceUnavailable, 1, false},
+		{"404 is not retried", http.MethodGet, 1, failWith(http.StatusNotFound), http.StatusNotFound, 1, false},
+		{"501 is not retried", http.MethodGet, 1, failWith(http.StatusNotImplemented), http.StatusNotImplemented, 1, false},
+		{"network errors exhausted", http.MethodGet, 10, closeConn, 0, 4, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, calls := faultServer(t, tc.failures, tc.fail)
+			resp, err := send(t, testRetryPolicy, tc.method, server.URL)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("got status %d, want an error", resp.StatusCode)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if resp.StatusCode != tc.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if got := atomic.LoadInt32(calls); got != tc.wantCalls {
+				t.Errorf("server got %d requests, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportHonoursRetryAfter(t *testing.T) {
+	server, calls := faultServer(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	start := time.Now()
+	resp, err := send(t, testRetryPolicy, http.MethodGet, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(calls) != 2 {
+		t.Errorf("got status %d after %d requests, want 200 after 2", resp.StatusCode, atomic.LoadInt32(calls))
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After of 1s honoured", elapsed)
+	}
+
+	server, calls = faultServer(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	resp, err = send(t, testRetryPolicy, http.MethodGet, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || atomic.LoadInt32(calls) != 1 {
+		t.Errorf("got status %d after %d requests, want 503 without retry", resp.StatusCode, atomic.LoadInt32(calls))
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	policy, err := NewRetryPolicy(map[string]string{})
+	if err != nil || policy != DefaultRetryPolicy() {
+		t.Errorf("got %+v, %v, want the default policy", policy, err)
+	}
+
+	policy, err = NewRetryPolicy(map[string]string{
+		"max_retries": "0", "retry_initial_delay": "250ms", "retry_max_delay": "10"})
+	want := RetryPolicy{MaxRetries: 0, InitialDelay: 250 * time.Millisecond, MaxDelay: 10 * time.Second}
+	if err != nil || policy != want {
+		t.Errorf("got %+v, %v, want %+v", policy, err, want)
+	}
+
+	for _, conf := range []map[string]string{
+		{"max_retries": "-1"},
+		{"max_retries": "many"},
+		{"retry_initial_delay": "soon"},
+		{"retry_max_delay": "0s"},
+	} {
+		if _, err := NewRetryPolicy(conf); err == nil {
+			t.Errorf("%v: got no error", conf)
+		}
+	}
+}
+
+func TestBackoffIsBounded(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		max := policy.InitialDelay << uint(attempt)
+		if max > policy.MaxDelay {
+			max = policy.MaxDelay
+		}
+		if d := policy.backoff(attempt); d < max/2 || d > max {
+			t.Errorf("attempt %d: backoff %v, want between %v and %v", attempt, d, max/2, max)
+		}
+	}
+}
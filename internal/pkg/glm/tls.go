@@ -0,0 +1,64 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"io/ioutil"
+	"strconv"
+)
+
+// NewTLSConfig builds the TLS configuration used to talk to the GLM portal from
+// the [glm_credentials] section of plugin.conf. Certificates are verified against
+// the system roots unless ca_bundle names a PEM file with additional CAs.
+// client_cert and client_key enable mutual TLS, and insecure_skip_verify=true
+// turns verification off altogether.
+func NewTLSConfig(glmCredDetails map[string]string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if val := glmCredDetails[constants.INSECURE_SKIP_VERIFY]; val != "" {
+		insecure, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' of %s in plugin.conf", val, constants.INSECURE_SKIP_VERIFY)
+		}
+		if insecure {
+			log.Warnf("TLS certificate verification of the GLM portal is disabled by %s",
+				constants.INSECURE_SKIP_VERIFY)
+		}
+		tlsConfig.InsecureSkipVerify = insecure
+	}
+
+	if caBundle := glmCredDetails[constants.CA_BUNDLE]; caBundle != "" {
+		pem, err := ioutil.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s '%s': %v", constants.CA_BUNDLE, caBundle, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s '%s'", constants.CA_BUNDLE, caBundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientCert := glmCredDetails[constants.CLIENT_CERT]
+	clientKey := glmCredDetails[constants.CLIENT_KEY]
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, fmt.Errorf("both %s and %s must be set in plugin.conf", constants.CLIENT_CERT,
+				constants.CLIENT_KEY)
+		}
+		cert, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate '%s': %v", clientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
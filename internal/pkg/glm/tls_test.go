@@ -0,0 +1,174 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCAPEM writes the self-signed certificate of srv, which acts as its own
+// CA, to a PEM file.
+func writeCAPEM(t *testing.T, srv *httptest.Server) string {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := ioutil.WriteFile(path, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeClientCert writes a self-signed client certificate and its key to PEM
+// files.
+func writeClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "singularity"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		0600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, certFile, keyFile
+}
+
+// getWithTLSConfig makes a request to srv with the TLS configuration built
+// from conf.
+func getWithTLSConfig(t *testing.T, srv *httptest.Server, conf map[string]string) error {
+	tlsConfig, err := NewTLSConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestNewTLSConfigVerifiesServer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caBundle := writeCAPEM(t, srv)
+
+	tests := []struct {
+		name      string
+		conf      map[string]string
+		wantError bool
+	}{
+		// the certificate of the server is self-signed
+		{"system roots", map[string]string{}, true},
+		{"ca_bundle", map[string]string{"ca_bundle": caBundle}, false},
+		{"insecure_skip_verify", map[string]string{"insecure_skip_verify": "true"}, false},
+		{"insecure_skip_verify false", map[string]string{"insecure_skip_verify": "false"}, true},
+		{"insecure_skip_verify 1", map[string]string{"insecure_skip_verify": "1"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := getWithTLSConfig(t, srv, test.conf)
+			if test.wantError {
+				if err == nil || !strings.Contains(err.Error(), "certificate") {
+					t.Errorf("got %v, want a certificate error", err)
+				}
+			} else if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigClientCert(t *testing.T) {
+	cert, certFile, keyFile := writeClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	caBundle := writeCAPEM(t, srv)
+
+	conf := map[string]string{"ca_bundle": caBundle, "client_cert": certFile, "client_key": keyFile}
+	if err := getWithTLSConfig(t, srv, conf); err != nil {
+		t.Errorf("with client certificate: %v", err)
+	}
+	if err := getWithTLSConfig(t, srv, map[string]string{"ca_bundle": caBundle}); err == nil {
+		t.Error("without client certificate: request succeeded")
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	_, certFile, keyFile := writeClientCert(t)
+	notPEM := filepath.Join(t.TempDir(), "not.pem")
+	if err := ioutil.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		conf      map[string]string
+		wantError string
+	}{
+		{map[string]string{"insecure_skip_verify": "yes"}, "invalid value 'yes' of insecure_skip_verify"},
+		{map[string]string{"ca_bundle": filepath.Join(t.TempDir(), "missing.pem")}, "failed to read ca_bundle"},
+		{map[string]string{"ca_bundle": notPEM}, "no certificates found in ca_bundle"},
+		{map[string]string{"client_cert": certFile}, "both client_cert and client_key must be set"},
+		{map[string]string{"client_key": keyFile}, "both client_cert and client_key must be set"},
+		{map[string]string{"client_cert": certFile, "client_key": notPEM}, "failed to load client certificate"},
+	}
+	for _, test := range tests {
+		_, err := NewTLSConfig(test.conf)
+		if err == nil || !strings.Contains(err.Error(), test.wantError) {
+			t.Errorf("%v: got %v, want error %q", test.conf, err, test.wantError)
+		}
+	}
+
+	tlsConfig, err := NewTLSConfig(map[string]string{"client_cert": certFile, "client_key": keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tlsConfig.Certificates) != 1 || tlsConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("got %d certificates and minimum version %x", len(tlsConfig.Certificates), tlsConfig.MinVersion)
+	}
+}
@@ -0,0 +1,131 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TraceRecord is one HTTP exchange with GLM, written as a line of the trace
+// file. Secrets in headers and bodies are masked.
+type TraceRecord struct {
+	Time            time.Time   `json:"time"`
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	Status          int         `json:"status,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+	LatencyMS       float64     `json:"latency_ms"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// Tracer appends a TraceRecord to its file for every HTTP exchange. The file
+// is opened for each record, so that commands running concurrently can share
+// it.
+type Tracer struct {
+	File string
+	mu   sync.Mutex
+}
+
+// NewTracer returns the tracer of the trace key, given on the command line or
+// in the [glm_credentials] section of plugin.conf, or else of the
+// GLM_TRACE_FILE environment variable. It returns nil when tracing is off.
+func NewTracer(glmCredDetails map[string]string) (*Tracer, error) {
+	file := glmCredDetails[constants.TRACE]
+	if file == "" {
+		file = os.Getenv(constants.GLM_TRACE_FILE_ENV)
+	}
+	if file == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trace directory for '%s': %v", file, err)
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file '%s': %v", file, err)
+	}
+	f.Close()
+	return &Tracer{File: file}, nil
+}
+
+// Record appends record to the trace file. A trace that cannot be written is
+// logged and does not fail the request.
+func (t *Tracer) Record(record *TraceRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Warnf("failed to encode trace record: %v", err)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f, err := os.OpenFile(t.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Warnf("failed to open trace file '%s': %v", t.File, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		log.Warnf("failed to write trace file '%s': %v", t.File, err)
+	}
+}
+
+// traceTransport records the requests sent through base and their responses.
+// It sits below the retry transport, so that every attempt is recorded.
+type traceTransport struct {
+	base   http.RoundTripper
+	tracer *Tracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := &TraceRecord{
+		Time:           time.Now().UTC(),
+		Method:         req.Method,
+		URL:            redact.String(req.URL.String()),
+		RequestHeaders: redact.Header(req.Header),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		record.RequestBody = redact.String(string(body))
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		record.Status = resp.StatusCode
+		record.ResponseHeaders = redact.Header(resp.Header)
+		var body []byte
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		record.ResponseBody = redact.String(string(body))
+		if err != nil {
+			resp = nil
+		}
+	}
+	record.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		record.Error = err.Error()
+	}
+	t.tracer.Record(record)
+	return resp, err
+}
@@ -0,0 +1,108 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readTrace(t *testing.T, file string) []TraceRecord {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []TraceRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record TraceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid trace line %s: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNewTracer(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.GLM_TRACE_FILE_ENV, "")
+	if tracer, err := NewTracer(map[string]string{}); tracer != nil || err != nil {
+		t.Errorf("tracing without a trace file: got %+v, %v", tracer, err)
+	}
+
+	t.Setenv(constants.GLM_TRACE_FILE_ENV, filepath.Join(dir, "env.jsonl"))
+	tracer, err := NewTracer(map[string]string{})
+	if err != nil || tracer.File != filepath.Join(dir, "env.jsonl") {
+		t.Errorf("got %+v, %v, want the trace file of the environment", tracer, err)
+	}
+	tracer, err = NewTracer(map[string]string{constants.TRACE: filepath.Join(dir, "sub", "trace.jsonl")})
+	if err != nil || tracer.File != filepath.Join(dir, "sub", "trace.jsonl") {
+		t.Errorf("got %+v, %v, want the trace key to take precedence", tracer, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub", "trace.jsonl")); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := NewTracer(map[string]string{constants.TRACE: dir}); err == nil {
+		t.Error("got no error for a directory")
+	}
+}
+
+func TestTraceRecordsExchanges(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trace.jsonl")
+	server, _ := faultServer(t, 1, failWith(http.StatusServiceUnavailable))
+	transport := NewTransport(server.URL, testMembership,
+		NewHTTPClient(nil, testRetryPolicy, nil, nil, &Tracer{File: file}), staticToken)
+	err := transport.Do(context.Background(), &Request{Operation: "update volume", Method: http.MethodPut,
+		Path: "rest/v1/volumes/vol-1", Body: map[string]string{"name": "vol1", "password": "s3cret"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := readTrace(t, file)
+	if len(records) != 2 {
+		t.Fatalf("got %d trace records, want one per attempt", len(records))
+	}
+	for i, wantStatus := range []int{http.StatusServiceUnavailable, http.StatusOK} {
+		record := records[i]
+		if record.Method != http.MethodPut || record.URL != server.URL+"/rest/v1/volumes/vol-1" ||
+			record.Status != wantStatus || record.LatencyMS <= 0 || record.Time.IsZero() {
+			t.Errorf("record %d: got %+v", i, record)
+		}
+		if got := record.RequestHeaders.Get("Authorization"); got != "Bearer "+redact.Mask {
+			t.Errorf("record %d: got Authorization %q", i, got)
+		}
+		if record.RequestHeaders.Get("Membership") != testMembership {
+			t.Errorf("record %d: got headers %v", i, record.RequestHeaders)
+		}
+		if !strings.Contains(record.RequestBody, `"name":"vol1"`) || strings.Contains(record.RequestBody, "s3cret") {
+			t.Errorf("record %d: got request body %s", i, record.RequestBody)
+		}
+	}
+	if records[1].ResponseBody != `{"ok":true}` {
+		t.Errorf("got response body %s", records[1].ResponseBody)
+	}
+
+	closed, _ := faultServer(t, 0, nil)
+	closed.Close()
+	transport.Portal = closed.URL
+	transport.HTTPClient = NewHTTPClient(nil, RetryPolicy{}, nil, nil, &Tracer{File: file})
+	if err := transport.Do(context.Background(), &Request{Operation: "list volumes", Method: http.MethodGet,
+		Path: "rest/v1/volumes"}, nil); err == nil {
+		t.Fatal("got no error from a closed server")
+	}
+	records = readTrace(t, file)
+	if len(records) != 3 || records[2].Status != 0 || records[2].Error == "" {
+		t.Errorf("failed exchange: got %+v", records[len(records)-1])
+	}
+}
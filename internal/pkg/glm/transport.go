@@ -0,0 +1,190 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+// Package glm is the transport used for every request to the GLM portal. It
+// authenticates requests with the session token, adds the Membership and
+// API-Version headers, retries transient failures and decodes GLM errors into
+// *model.APIError. The GLM API client and the endpoints the API client does
+// not cover, such as rest/volumeflavors, are both built on it.
+package glm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TokenFunc returns the session token authenticating GLM requests.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// Transport sends requests to a GLM portal on behalf of a membership.
+type Transport struct {
+	Portal       string
+	MembershipID string
+	APIVersion   string
+	HTTPClient   *http.Client
+	Token        TokenFunc
+}
+
+func NewTransport(portal, membershipID string, httpClient *http.Client, token TokenFunc) *Transport {
+	return &Transport{
+		Portal:       portal,
+		MembershipID: membershipID,
+		APIVersion:   constants.GLM_CLIENT_VERSION,
+		HTTPClient:   httpClient,
+		Token:        token,
+	}
+}
+
+// Request is a request sent with Transport.Do.
+type Request struct {
+	// Operation names the request in errors, e.g. "list volume flavors".
+	Operation string
+	Method    string
+	// Path is relative to the portal, or to BaseURL when it is set, for
+	// requests to other services such as the auth service.
+	Path    string
+	BaseURL string
+	Query   url.Values
+	// Body is sent JSON encoded.
+	Body interface{}
+	// Anonymous requests carry neither the session token nor the Membership
+	// header, e.g. those logging in.
+	Anonymous bool
+}
+
+// Do sends req and decodes the JSON response into out, unless out is nil.
+func (t *Transport) Do(ctx context.Context, req *Request, out interface{}) error {
+	httpReq, err := t.newRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := t.setHeaders(httpReq, req.Anonymous); err != nil {
+		return err
+	}
+	log.Infof("%s %s headers = %v", httpReq.Method, httpReq.URL, redact.Header(httpReq.Header))
+
+	resp, err := t.HTTPClient.Do(httpReq)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return TransportError(req.Operation, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return TransportError(req.Operation, err)
+	}
+	log.Infof("%s response %d: %s", req.Operation, resp.StatusCode, redact.String(string(body)))
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return StatusError(req.Operation, resp, body)
+	}
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return &model.APIError{
+			Category:   model.ErrorInternal,
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("%s failed: cannot parse the response: %v", req.Operation, err),
+			RequestID:  resp.Header.Get(constants.REQUEST_ID_HEADER),
+			Err:        err,
+		}
+	}
+	return nil
+}
+
+func (t *Transport) newRequest(ctx context.Context, req *Request) (*http.Request, error) {
+	base := req.BaseURL
+	if base == "" {
+		base = t.Portal
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, model.NewError(model.ErrorValidation, fmt.Errorf("invalid GLM URL '%s': %v", base, err))
+	}
+	// only host name without scheme and port does not parse well
+	if u.Host == "" {
+		u.Host = u.Path
+		u.Path = ""
+	}
+	if u.Scheme == "" {
+		u.Scheme = "https"
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + strings.TrimLeft(req.Path, "/")
+	u.RawQuery = req.Query.Encode()
+
+	var body io.Reader
+	if req.Body != nil {
+		data, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: cannot encode the request: %v", req.Operation, err)
+		}
+		body = bytes.NewReader(data)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	return httpReq, nil
+}
+
+// setHeaders adds the headers every GLM request carries. Headers the request
+// already has are kept.
+func (t *Transport) setHeaders(req *http.Request, anonymous bool) error {
+	if req.Header.Get("API-Version") == "" && t.APIVersion != "" {
+		req.Header.Set("API-Version", t.APIVersion)
+	}
+	if anonymous {
+		return nil
+	}
+	if req.Header.Get("Membership") == "" {
+		req.Header.Set("Membership", t.MembershipID)
+	}
+	if req.Header.Get("Authorization") == "" {
+		token, err := t.Token(req.Context())
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// RoundTrip sends a request of the GLM API client, adding the session token
+// and the GLM headers.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if err := t.setHeaders(req, false); err != nil {
+		return nil, err
+	}
+	base := t.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// NewAPIClient returns a GLM API client sending its requests through t.
+func (t *Transport) NewAPIClient(debug bool) *glmClient.APIClient {
+	cfg := glmClient.NewConfiguration()
+	cfg.BasePath = strings.TrimRight(t.Portal, "/") + constants.REST_API_VERSION
+	cfg.HTTPClient = &http.Client{Transport: t}
+	cfg.Debug = debug
+	return glmClient.NewAPIClient(cfg)
+}
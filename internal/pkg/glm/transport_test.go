@@ -0,0 +1,185 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const testMembership = "D23C0865-01C2-4401-8280-E3397CBB35B5"
+
+func staticToken(ctx context.Context) (string, error) {
+	return "token", nil
+}
+
+// recordingServer answers every request with status and body and keeps the
+// last request.
+func recordingServer(t *testing.T, status int, body string) (*httptest.Server, **http.Request, *[]byte) {
+	var last *http.Request
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		lastBody, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &last, &lastBody
+}
+
+func newTestTransport(portal string) *Transport {
+	return NewTransport(portal, testMembership, NewHTTPClient(nil, RetryPolicy{}, nil, nil, nil), staticToken)
+}
+
+func TestTransportDo(t *testing.T) {
+	server, last, lastBody := recordingServer(t, http.StatusOK, `[{"id":"pool-1","cluster_name":"dc1"}]`)
+	var pools []model.CapacityPools
+	err := newTestTransport(server.URL).Do(context.Background(), &Request{Operation: "list capacitypools",
+		Method: http.MethodPut, Path: "rest/capacitypools", Query: url.Values{"limit": {"10"}},
+		Body: map[string]string{"name": "pool"}}, &pools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 1 || pools[0].ID != "pool-1" {
+		t.Errorf("got %+v", pools)
+	}
+	req := *last
+	if req.URL.Path != "/rest/capacitypools" || req.URL.RawQuery != "limit=10" {
+		t.Errorf("got request for %s", req.URL)
+	}
+	for header, want := range map[string]string{
+		"Authorization": "Bearer token",
+		"Membership":    testMembership,
+		"API-Version":   "0.14.0",
+		"Content-Type":  "application/json",
+	} {
+		if got := req.Header.Get(header); got != want {
+			t.Errorf("%s: got %q, want %q", header, got, want)
+		}
+	}
+	var body map[string]string
+	if err := json.Unmarshal(*lastBody, &body); err != nil || body["name"] != "pool" {
+		t.Errorf("got body %s", *lastBody)
+	}
+}
+
+func TestTransportDoAnonymous(t *testing.T) {
+	server, last, _ := recordingServer(t, http.StatusOK, `{"auth_url":"https://auth"}`)
+	transport := newTestTransport("http://unused")
+	transport.Token = func(ctx context.Context) (string, error) {
+		t.Error("anonymous request asked for the session token")
+		return "", nil
+	}
+	var info model.AuthSvcInfo
+	err := transport.Do(context.Background(), &Request{Operation: "get auth service info", Method: http.MethodGet,
+		BaseURL: server.URL, Path: "/info/authsvcinfo", Anonymous: true}, &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AuthURL != "https://auth" {
+		t.Errorf("got %+v", info)
+	}
+	if (*last).Header.Get("Authorization") != "" || (*last).Header.Get("Membership") != "" {
+		t.Errorf("anonymous request sent %v", (*last).Header)
+	}
+}
+
+func TestTransportDoErrors(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		portal       string
+		token        TokenFunc
+		wantCategory model.ErrorCategory
+		wantStatus   int
+		wantSession  bool
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{"message":"pool not found"}`,
+			wantCategory: model.ErrorNotFound, wantStatus: http.StatusNotFound},
+		{name: "expired token", status: http.StatusUnauthorized, body: "Token is expired",
+			wantCategory: model.ErrorUnauthorized, wantStatus: http.StatusUnauthorized, wantSession: true},
+		{name: "unparsable response", status: http.StatusOK, body: "<html>",
+			wantCategory: model.ErrorInternal, wantStatus: http.StatusOK},
+		{name: "no session", status: http.StatusOK, body: "[]",
+			token:        func(ctx context.Context) (string, error) { return "", model.TokenError },
+			wantCategory: model.ErrorUnauthorized, wantSession: true},
+		{name: "portal down", portal: closed.URL, wantCategory: model.ErrorTransport},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			portal := tc.portal
+			if portal == "" {
+				server, _, _ := recordingServer(t, tc.status, tc.body)
+				portal = server.URL
+			}
+			transport := newTestTransport(portal)
+			if tc.token != nil {
+				transport.Token = tc.token
+			}
+			var pools []model.CapacityPools
+			err := transport.Do(context.Background(), &Request{Operation: "list capacitypools",
+				Method: http.MethodGet, Path: "rest/capacitypools"}, &pools)
+			if got := model.Category(err); got != tc.wantCategory {
+				t.Fatalf("got %v (%v), want category %s", err, got, tc.wantCategory)
+			}
+			var apiErr *model.APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode != tc.wantStatus {
+				t.Errorf("got status %d, want %d", apiErr.StatusCode, tc.wantStatus)
+			}
+			if tc.wantStatus != 0 && apiErr.RequestID != "req-1" {
+				t.Errorf("got request id %q", apiErr.RequestID)
+			}
+			session := errors.Is(err, TokenExpiredError) || errors.Is(err, model.TokenError)
+			if session != tc.wantSession {
+				t.Errorf("got session error %v, want %v", session, tc.wantSession)
+			}
+		})
+	}
+}
+
+func TestAPIClientUsesTransport(t *testing.T) {
+	server, last, _ := recordingServer(t, http.StatusOK, `[{"ID":"vol-1","Name":"vol1","State":"allocated"}]`)
+	r := newTestTransport(server.URL).NewAPIClient(false)
+	volumes, _, err := r.VolumesApi.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(volumes) != 1 || volumes[0].ID != "vol-1" {
+		t.Errorf("got %+v", volumes)
+	}
+	if (*last).URL.Path != "/rest/v1/volumes" {
+		t.Errorf("got request for %s", (*last).URL.Path)
+	}
+	if (*last).Header.Get("Authorization") != "Bearer token" || (*last).Header.Get("Membership") != testMembership {
+		t.Errorf("got headers %v", (*last).Header)
+	}
+
+	server, _, _ = recordingServer(t, http.StatusNotFound, `{"message":"volume not found"}`)
+	r = newTestTransport(server.URL).NewAPIClient(false)
+	_, httpResp, err := r.VolumesApi.GetByID(context.Background(), "vol-2")
+	err = APIClientError("get volume", httpResp, err)
+	if model.Category(err) != model.ErrorNotFound || err.Error() != "get volume failed: volume not found (HTTP 404)" {
+		t.Errorf("got %v", err)
+	}
+
+	transport := newTestTransport(server.URL)
+	transport.Token = func(ctx context.Context) (string, error) { return "", model.TokenError }
+	_, httpResp, err = transport.NewAPIClient(false).VolumesApi.List(context.Background())
+	if err = APIClientError("list volumes", httpResp, err); err != model.TokenError {
+		t.Errorf("got %v, want TokenError", err)
+	}
+}
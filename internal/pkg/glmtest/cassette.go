@@ -0,0 +1,191 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glmtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Environment variables selecting the portal a cassette is recorded from.
+const (
+	CassetteRecordEnv       = "GLM_CASSETTE_RECORD"
+	CassettePortalEnv       = "GLM_CASSETTE_PORTAL"
+	CassetteMembershipIDEnv = "GLM_CASSETTE_MEMBERSHIP_ID"
+)
+
+// replayPortal is the portal of replayed cassettes. Requests are matched
+// without their host, so any URL would do.
+const replayPortal = "https://glm.example.com"
+
+// Cassette replays the HTTP exchanges of a trace file, as written by the
+// plugin with trace= or GLM_TRACE_FILE. A request is answered with the first
+// exchange not replayed yet that has the same method, path and query.
+// Secrets are masked in trace files, so request bodies and headers are not
+// compared.
+type Cassette struct {
+	File    string
+	mu      sync.Mutex
+	records []glm.TraceRecord
+	played  []bool
+}
+
+// LoadCassette reads the trace file file.
+func LoadCassette(file string) (*Cassette, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c := &Cassette{File: file}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record glm.TraceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", file, line, err)
+		}
+		c.records = append(c.records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	c.played = make([]bool, len(c.records))
+	return c, nil
+}
+
+// RoundTrip replays the response recorded for req. A request the cassette
+// has no exchange for fails.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, record := range c.records {
+		if c.played[i] || record.Method != req.Method {
+			continue
+		}
+		u, err := url.Parse(record.URL)
+		if err != nil || u.Path != req.URL.Path || u.RawQuery != req.URL.RawQuery {
+			continue
+		}
+		c.played[i] = true
+		if record.Status == 0 {
+			return nil, errors.New(record.Error)
+		}
+		header := record.ResponseHeaders.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(record.Status) + " " + http.StatusText(record.Status),
+			StatusCode:    record.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(record.ResponseBody)),
+			ContentLength: int64(len(record.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%s has no recorded response left for %s %s", c.File, req.Method, req.URL.RequestURI())
+}
+
+// Unplayed returns the exchanges that were not replayed, as "METHOD URL".
+func (c *Cassette) Unplayed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unplayed []string
+	for i, record := range c.records {
+		if !c.played[i] {
+			unplayed = append(unplayed, record.Method+" "+record.URL)
+		}
+	}
+	return unplayed
+}
+
+// Target is the portal a cassette test talks to, with the client to reach it.
+type Target struct {
+	Portal       string
+	MembershipID string
+	Username     string
+	Password     string
+	HTTPClient   *http.Client
+}
+
+// UseCassette returns the target of a test replaying the cassette file. The
+// test fails when it does not replay all of the cassette.
+//
+// With GLM_CASSETTE_RECORD=true the test talks to the portal given by
+// GLM_CASSETTE_PORTAL and GLM_CASSETTE_MEMBERSHIP_ID instead, as the user
+// given by GLM_USERNAME and GLM_PASSWORD, and records the exchanges to file.
+func UseCassette(t testing.TB, file string) *Target {
+	if record, _ := strconv.ParseBool(os.Getenv(CassetteRecordEnv)); record {
+		target := &Target{
+			Portal:       os.Getenv(CassettePortalEnv),
+			MembershipID: os.Getenv(CassetteMembershipIDEnv),
+			Username:     os.Getenv(constants.GLM_USERNAME_ENV),
+			Password:     os.Getenv(constants.GLM_PASSWORD_ENV),
+		}
+		if target.Portal == "" || target.MembershipID == "" || target.Username == "" || target.Password == "" {
+			t.Fatalf("recording %s needs %s, %s, %s and %s", file, CassettePortalEnv, CassetteMembershipIDEnv,
+				constants.GLM_USERNAME_ENV, constants.GLM_PASSWORD_ENV)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		target.HTTPClient = glm.NewHTTPClient(nil, glm.DefaultRetryPolicy(), nil, nil, &glm.Tracer{File: file})
+		return target
+	}
+
+	cassette, err := LoadCassette(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if unplayed := cassette.Unplayed(); len(unplayed) > 0 && !t.Failed() {
+			t.Errorf("%s: exchanges not replayed: %v", file, unplayed)
+		}
+	})
+	// retried requests are in the cassette once per attempt
+	retries := glm.RetryPolicy{MaxRetries: constants.DEFAULT_MAX_RETRIES, InitialDelay: time.Millisecond,
+		MaxDelay: time.Millisecond}
+	return &Target{
+		Portal:       replayPortal,
+		MembershipID: MembershipID,
+		Username:     Username,
+		Password:     Password,
+		HTTPClient:   &http.Client{Transport: glm.NewRetryTransport(cassette, retries)},
+	}
+}
+
+// UseTempSessionCache points the session cache at a file of its own for the
+// duration of the test, so that the test starts logged out.
+func UseTempSessionCache(t testing.TB) {
+	saved := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(t.TempDir(), "sessions.json")
+	t.Cleanup(func() { model.SessionCacheFile = saved })
+}
@@ -0,0 +1,110 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glmtest
+
+import (
+	"context"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCassetteVolumeLifecycle replays testdata/glmtest_volume_lifecycle.jsonl.
+// The glmtest_ prefix marks cassettes recorded from NewServer rather than a
+// real portal: they show that a recorded lifecycle replays, not how a portal
+// answers. To record the lifecycle from a portal run the following and drop
+// the prefix of the file name.
+//
+//	GLM_CASSETTE_RECORD=true GLM_CASSETTE_PORTAL=https://... GLM_CASSETTE_MEMBERSHIP_ID=... \
+//	GLM_USERNAME=... GLM_PASSWORD=... go test -run TestCassetteVolumeLifecycle ./internal/pkg/glmtest
+func TestCassetteVolumeLifecycle(t *testing.T) {
+	ctx := context.Background()
+	target := UseCassette(t, filepath.Join("testdata", "glmtest_volume_lifecycle.jsonl"))
+	UseTempSessionCache(t)
+	cli := client.NewClient(target.Portal, target.Username, target.Password, target.MembershipID,
+		target.HTTPClient)
+	if err := cli.Login(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	flavors, err := cli.ListVolumeFlavors(ctx)
+	if err != nil || len(*flavors) == 0 {
+		t.Fatalf("list volume flavors: got %+v, %v", flavors, err)
+	}
+	volume, err := cli.CreateVolume(ctx, &model.Volume{Name: "cassette-vol", Capacity: 1,
+		FlavorID: (*flavors)[0].ID, LocationID: LocationID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.State != constants.VOLUME_STATE_ALLOCATED || volume.Name != "cassette-vol" {
+		t.Errorf("create volume: got %+v", volume)
+	}
+	volumes, err := cli.ListVolumes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, v := range *volumes {
+		found = found || v.VolumeID == volume.VolumeID
+	}
+	if !found {
+		t.Errorf("list volumes: %s not in %+v", volume.VolumeID, volumes)
+	}
+	deleted, err := cli.DeleteVolume(ctx, volume.VolumeID)
+	if err != nil || deleted.State != constants.STATE_DELETED {
+		t.Errorf("delete volume: got %+v, %v", deleted, err)
+	}
+}
+
+func TestCassetteReplay(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cassette.jsonl")
+	trace := `{"method":"GET","url":"https://glm/rest/v1/volumes/v1","status":503}
+{"method":"GET","url":"https://glm/rest/v1/volumes/v1","status":200,"response_body":"{\"ID\":\"v1\"}"}
+
+{"method":"GET","url":"https://glm/rest/v1/volumes?limit=1","status":0,"error":"connection reset"}
+`
+	if err := ioutil.WriteFile(file, []byte(trace), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cassette, err := LoadCassette(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(url string) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		return cassette.RoundTrip(req)
+	}
+
+	if resp, err := get("https://other-host/rest/v1/volumes/v1"); err != nil || resp.StatusCode != 503 {
+		t.Errorf("first exchange: got %v, %v", resp, err)
+	}
+	resp, err := get("https://other-host/rest/v1/volumes/v1")
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("second exchange: got %v, %v", resp, err)
+	}
+	if body, _ := ioutil.ReadAll(resp.Body); string(body) != `{"ID":"v1"}` {
+		t.Errorf("got body %s", body)
+	}
+	if _, err := get("https://glm/rest/v1/volumes/v1"); err == nil ||
+		!strings.Contains(err.Error(), "no recorded response left for GET /rest/v1/volumes/v1") {
+		t.Errorf("replayed exchange: got %v", err)
+	}
+	if got := cassette.Unplayed(); len(got) != 1 || got[0] != "GET https://glm/rest/v1/volumes?limit=1" {
+		t.Errorf("got unplayed %v", got)
+	}
+	if _, err := get("https://glm/rest/v1/volumes?limit=1"); err == nil || err.Error() != "connection reset" {
+		t.Errorf("recorded failure: got %v", err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("{\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCassette(file); err == nil || !strings.Contains(err.Error(), "cassette.jsonl:1") {
+		t.Errorf("invalid cassette: got %v", err)
+	}
+}
@@ -0,0 +1,523 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+// Package glmtest fakes the GLM portal for tests. Server serves the auth
+// service, volumes, volume attachments, volume flavors and capacity pools in
+// process, moving volumes and attachments through their states as a portal
+// does. A Cassette replays the HTTP exchanges of a trace file. The cassettes
+// under testdata were recorded from Server, not from a real portal.
+package glmtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Credentials and resources every Server starts with.
+const (
+	Username     = "glmtest@hpe.com"
+	Password     = "glmtest-Pa55word"
+	MembershipID = "D23C0865-01C2-4401-8280-E3397CBB35B5"
+	LocationID   = "1ad98170-993e-4bfc-8b84-e689ea9a429b"
+	FlavorID     = "0344e238-5a04-4310-a7b2-a969b5c7bc03"
+	FlavorName   = "Default"
+	PoolID       = "5b0f4e47-5f1e-4d38-9c40-3f4c1e7b8a21"
+	ClusterName  = "glmtest_cluster"
+	RequestID    = "glmtest-request"
+	// Ticket is the secret part of the MapR ticket of ready attachments, a
+	// base64 blob as in real tickets.
+	Ticket = "Z2xtdGVzdC10aWNrZXQtb2YtdGhlLWZha2UtcG9ydGFsLWZvci10ZXN0cw=="
+	// TokenLifetime is the lifetime of the session tokens issued by Server.
+	TokenLifetime = time.Hour
+)
+
+// authSvcInfoURL is client.AUTH_SVC_INFO. glmtest does not import the client
+// package, so that its tests can use Server.
+const authSvcInfoURL = "/info/authsvcinfo"
+
+// volumeTransitions and attachmentTransitions give the state following each
+// transitional state.
+var (
+	volumeTransitions = map[glmClient.VolumeState]glmClient.VolumeState{
+		glmClient.VOLUMESTATE_NEW:        glmClient.VOLUMESTATE_ALLOCATING,
+		glmClient.VOLUMESTATE_ALLOCATING: glmClient.VOLUMESTATE_ALLOCATED,
+		glmClient.VOLUMESTATE_DELETING:   glmClient.VOLUMESTATE_DELETED,
+	}
+	attachmentTransitions = map[glmClient.VaStateEnum]glmClient.VaStateEnum{
+		glmClient.VASTATEENUM_NEW:       glmClient.VASTATEENUM_EXPORTING,
+		glmClient.VASTATEENUM_EXPORTING: glmClient.VASTATEENUM_ATTACHING,
+		glmClient.VASTATEENUM_ATTACHING: glmClient.VASTATEENUM_READY,
+	}
+)
+
+// Server is a fake GLM portal and auth service.
+type Server struct {
+	*httptest.Server
+	// Steps is how many times a volume or attachment in a transitional
+	// state, such as allocating, is read before it moves to the next state.
+	// With 0 the next read already sees the final state.
+	Steps int
+	// PurgeDeleted makes a volume disappear once it is deleted, so that it is
+	// not found any more, as on portals that do not keep deleted volumes.
+	PurgeDeleted bool
+
+	mu          sync.Mutex
+	nextID      int
+	tokens      map[string]bool
+	refresh     map[string]bool
+	grants      []string
+	volumes     map[string]*volume
+	attachments map[string]*attachment
+	flavors     []model.VolumeFlavor
+	pools       []model.CapacityPools
+}
+
+type volume struct {
+	glmClient.Volume
+	reads int
+}
+
+type attachment struct {
+	glmClient.VolumeAttachment
+	reads int
+}
+
+// NewServer starts a fake portal that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		tokens:      map[string]bool{},
+		refresh:     map[string]bool{},
+		volumes:     map[string]*volume{},
+		attachments: map[string]*attachment{},
+		flavors:     []model.VolumeFlavor{{ID: FlavorID, Name: FlavorName}},
+		pools: []model.CapacityPools{{ID: PoolID, Name: "pool", ClusterName: ClusterName,
+			VolumeFlavors: []string{FlavorID}}},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(authSvcInfoURL, s.authSvcInfo)
+	mux.HandleFunc("/oauth/token", s.token)
+	mux.HandleFunc(constants.REST_API_VERSION+"/volumes", s.authorized(s.volumesHandler))
+	mux.HandleFunc(constants.REST_API_VERSION+"/volumes/", s.authorized(s.volumeHandler))
+	mux.HandleFunc(constants.REST_API_VERSION+"/volume-attachments", s.authorized(s.attachmentsHandler))
+	mux.HandleFunc(constants.REST_API_VERSION+"/volume-attachments/", s.authorized(s.attachmentHandler))
+	mux.HandleFunc("/"+constants.REST_VOLUME_FLAVOR_URL, s.authorized(s.flavorsHandler))
+	mux.HandleFunc("/"+constants.REST_CAPACITYPOOLS_URL, s.authorized(s.poolsHandler))
+	mux.HandleFunc("/"+constants.REST_CAPACITYPOOLS_URL+"/", s.authorized(s.poolHandler))
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// CredDetails returns the [glm_credentials] section of a plugin.conf for the
+// server.
+func (s *Server) CredDetails() map[string]string {
+	return map[string]string{constants.GLM_PORTAL: s.URL, constants.MEMBERSHIP_ID: MembershipID}
+}
+
+// ExpireSessions makes every session token issued so far invalid, as if the
+// tokens expired.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+// Grants returns the grant types requested from the auth service so far.
+func (s *Server) Grants() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.grants...)
+}
+
+// AddVolume adds an allocated volume and returns it.
+func (s *Server) AddVolume(name string, capacity int64) glmClient.Volume {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.newVolume(glmClient.NewVolume{Name: name, FlavorID: FlavorID, Capacity: capacity, LocationID: LocationID})
+	v.State = glmClient.VOLUMESTATE_ALLOCATED
+	return v.Volume
+}
+
+// Volume returns the volume with id as the portal currently has it, without
+// counting as a read.
+func (s *Server) Volume(id string) (glmClient.Volume, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.volumes[id]
+	if !ok {
+		return glmClient.Volume{}, false
+	}
+	return v.Volume, true
+}
+
+// SetVolumeState forces the state of the volume with id, e.g. to failed.
+func (s *Server) SetVolumeState(id string, state glmClient.VolumeState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.volumes[id]; ok {
+		v.State = state
+		v.reads = 0
+	}
+}
+
+func (s *Server) authSvcInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, model.AuthSvcInfo{AuthURL: s.URL, AuthClientID: "glmtest-client",
+		AuthAudience: "glmtest-audience"})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	var req map[string]string
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+		writeError(w, http.StatusBadRequest, "invalid token request")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grants = append(s.grants, req["grant_type"])
+	switch req["grant_type"] {
+	case constants.GRANT_PASSWORD_REALM:
+		if req["username"] != Username || req["password"] != Password {
+			writeError(w, http.StatusForbidden, "Wrong email or password.")
+			return
+		}
+	case constants.GRANT_REFRESH_TOKEN:
+		if !s.refresh[req["refresh_token"]] {
+			writeError(w, http.StatusForbidden, "Unknown or invalid refresh token.")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported grant type")
+		return
+	}
+	token := makeToken(time.Now().Add(TokenLifetime), len(s.grants))
+	refresh := fmt.Sprintf("v1.glmtest-refresh-%d", len(s.grants))
+	s.tokens[token] = true
+	s.refresh[refresh] = true
+	writeJSON(w, http.StatusOK, model.AuthResponse{IDToken: token, AccessToken: token, RefreshToken: refresh,
+		TokenType: "Bearer", ExpiresIn: int64(TokenLifetime / time.Second)})
+}
+
+// makeToken returns an unsigned JWT carrying the claims the plugin reads.
+func makeToken(expiry time.Time, serial int) string {
+	return strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)),
+		base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"email":"%s","exp":%d}`,
+			Username, expiry.Unix()))),
+		base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("glmtest-signature-%d", serial))),
+	}, ".")
+}
+
+// authorized rejects requests without a valid session token or membership
+// the way the portal does.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		valid := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		s.mu.Unlock()
+		if !valid {
+			writeError(w, http.StatusUnauthorized, "Token is expired")
+			return
+		}
+		if r.Header.Get("Membership") != MembershipID {
+			writeError(w, http.StatusForbidden, "not a member of the project")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next(w, r)
+	}
+}
+
+func (s *Server) volumesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		volumes := []glmClient.Volume{}
+		for _, id := range s.volumeIDs() {
+			if v := s.read(id); v.State != glmClient.VOLUMESTATE_DELETED {
+				volumes = append(volumes, v.Volume)
+			}
+		}
+		writeJSON(w, http.StatusOK, volumes)
+	case http.MethodPost:
+		var req glmClient.NewVolume
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Name == "" || req.Capacity <= 0 || req.LocationID == "" {
+			writeError(w, http.StatusBadRequest, "Name, Capacity and LocationID are required")
+			return
+		}
+		if !s.hasFlavor(req.FlavorID) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("volume flavor %s not found", req.FlavorID))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.newVolume(req).Volume)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) volumeHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, constants.REST_API_VERSION+"/volumes/")
+	if _, ok := s.volumes[id]; !ok {
+		writeError(w, http.StatusNotFound, "volume not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		v := s.read(id)
+		if s.PurgeDeleted && v.State == glmClient.VOLUMESTATE_DELETED {
+			delete(s.volumes, id)
+			writeError(w, http.StatusNotFound, "volume not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, v.Volume)
+	case http.MethodPut:
+		var req glmClient.Volume
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		v := s.read(id)
+		if req.ETag != v.ETag {
+			writeError(w, http.StatusPreconditionFailed, "volume was modified, ETag does not match")
+			return
+		}
+		if req.Capacity < v.Capacity {
+			writeError(w, http.StatusBadRequest, "Capacity cannot be reduced")
+			return
+		}
+		v.Description = req.Description
+		if req.Capacity > v.Capacity {
+			v.Capacity = req.Capacity
+			// a growing volume is allocated again
+			if v.State == glmClient.VOLUMESTATE_ALLOCATED {
+				v.State = glmClient.VOLUMESTATE_ALLOCATING
+				v.reads = 0
+			}
+		}
+		etag, _ := strconv.Atoi(v.ETag)
+		v.ETag = strconv.Itoa(etag + 1)
+		v.Modified = time.Now().UTC()
+		writeJSON(w, http.StatusOK, v.Volume)
+	case http.MethodDelete:
+		v := s.volumes[id]
+		for _, a := range s.attachments {
+			if a.VolumeID == id && a.State != glmClient.VASTATEENUM_DELETED {
+				writeError(w, http.StatusConflict, "volume is attached")
+				return
+			}
+		}
+		if v.State != glmClient.VOLUMESTATE_DELETED {
+			v.State = glmClient.VOLUMESTATE_DELETING
+			v.reads = 0
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) attachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		attachments := []glmClient.VolumeAttachment{}
+		for _, id := range s.attachmentIDs() {
+			if a := s.readAttachment(id); a.State != glmClient.VASTATEENUM_DELETED {
+				attachments = append(attachments, a.VolumeAttachment)
+			}
+		}
+		writeJSON(w, http.StatusOK, attachments)
+	case http.MethodPost:
+		var req glmClient.NewVolumeAttachment
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		v, ok := s.volumes[req.VolumeID]
+		if !ok || v.State == glmClient.VOLUMESTATE_DELETED {
+			writeError(w, http.StatusNotFound, "volume not found")
+			return
+		}
+		if v.State != glmClient.VOLUMESTATE_ALLOCATED && v.State != glmClient.VOLUMESTATE_VISIBLE {
+			writeError(w, http.StatusConflict, fmt.Sprintf("volume is %s", v.State))
+			return
+		}
+		s.nextID++
+		a := &attachment{VolumeAttachment: glmClient.VolumeAttachment{
+			ID:       makeID(s.nextID),
+			ETag:     fmt.Sprint(s.nextID),
+			Name:     req.Name,
+			Created:  time.Now().UTC(),
+			Modified: time.Now().UTC(),
+			VolumeID: req.VolumeID,
+			State:    glmClient.VASTATEENUM_EXPORTING,
+		}}
+		s.attachments[a.ID] = a
+		writeJSON(w, http.StatusOK, a.VolumeAttachment)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) attachmentHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, constants.REST_API_VERSION+"/volume-attachments/")
+	a, ok := s.attachments[id]
+	if !ok || a.State == glmClient.VASTATEENUM_DELETED {
+		writeError(w, http.StatusNotFound, "volume attachment not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.readAttachment(id).VolumeAttachment)
+	case http.MethodDelete:
+		a.State = glmClient.VASTATEENUM_DELETED
+		if v, ok := s.volumes[a.VolumeID]; ok && v.State == glmClient.VOLUMESTATE_VISIBLE {
+			v.State = glmClient.VOLUMESTATE_ALLOCATED
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) flavorsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.flavors)
+}
+
+func (s *Server) poolsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.pools)
+}
+
+func (s *Server) poolHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/"+constants.REST_CAPACITYPOOLS_URL+"/")
+	for _, pool := range s.pools {
+		if pool.ID == id {
+			writeJSON(w, http.StatusOK, pool)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "capacity pool not found")
+}
+
+func (s *Server) hasFlavor(id string) bool {
+	for _, flavor := range s.flavors {
+		if flavor.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) newVolume(req glmClient.NewVolume) *volume {
+	s.nextID++
+	v := &volume{Volume: glmClient.Volume{
+		ID:          makeID(s.nextID),
+		ETag:        fmt.Sprint(s.nextID),
+		Name:        req.Name,
+		Created:     time.Now().UTC(),
+		Modified:    time.Now().UTC(),
+		Description: req.Description,
+		FlavorID:    req.FlavorID,
+		Capacity:    req.Capacity,
+		Shareable:   req.Shareable,
+		LocationID:  req.LocationID,
+		State:       glmClient.VOLUMESTATE_ALLOCATING,
+		Status:      glmClient.VOLUMESTATUS_OK,
+	}}
+	s.volumes[v.ID] = v
+	return v
+}
+
+// read returns the volume with id, moving it to its next state once it has
+// been read Steps times in a transitional state.
+func (s *Server) read(id string) *volume {
+	v := s.volumes[id]
+	for next, ok := volumeTransitions[v.State]; ok; next, ok = volumeTransitions[v.State] {
+		if v.reads < s.Steps {
+			v.reads++
+			break
+		}
+		v.State = next
+		v.Modified = time.Now().UTC()
+		v.reads = 0
+		if s.Steps > 0 {
+			break
+		}
+	}
+	return v
+}
+
+// readAttachment returns the attachment with id, moving it through exporting
+// and attaching to ready like read. A ready attachment carries the FUSE
+// configuration and makes its volume visible.
+func (s *Server) readAttachment(id string) *attachment {
+	a := s.attachments[id]
+	for next, ok := attachmentTransitions[a.State]; ok; next, ok = attachmentTransitions[a.State] {
+		if a.reads < s.Steps {
+			a.reads++
+			break
+		}
+		a.State = next
+		a.Modified = time.Now().UTC()
+		a.reads = 0
+		if s.Steps > 0 {
+			break
+		}
+	}
+	if a.State == glmClient.VASTATEENUM_READY && a.FSConfig == nil {
+		a.FSConfig = &glmClient.VafsConfig{StorageID: ClusterName, UserName: "glmtest",
+			Ticket: ClusterName + " " + Ticket, TicketExpiryTime: time.Now().Add(TokenLifetime).UTC().String()}
+		if v, ok := s.volumes[a.VolumeID]; ok && v.State == glmClient.VOLUMESTATE_ALLOCATED {
+			v.State = glmClient.VOLUMESTATE_VISIBLE
+		}
+	}
+	return a
+}
+
+// makeID returns a deterministic UUID, so that recorded exchanges do not
+// change between runs. IDs sort in creation order.
+func makeID(n int) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+}
+
+func (s *Server) volumeIDs() []string {
+	ids := make([]string, 0, len(s.volumes))
+	for id := range s.volumes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *Server) attachmentIDs() []string {
+	ids := make([]string, 0, len(s.attachments))
+	for id := range s.attachments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(constants.REQUEST_ID_HEADER, RequestID)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,199 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package glmtest
+
+import (
+	"context"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func newTestClient(t *testing.T, s *Server, password string) client.ClientInterface {
+	UseTempSessionCache(t)
+	return client.NewClient(s.URL, Username, password, MembershipID,
+		glm.NewHTTPClient(nil, glm.RetryPolicy{}, nil, nil, nil))
+}
+
+func TestServerVolumeLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(t)
+	cli := newTestClient(t, s, Password)
+	if err := cli.Login(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	flavors, err := cli.ListVolumeFlavors(ctx)
+	if err != nil || len(*flavors) != 1 || (*flavors)[0].Name != FlavorName {
+		t.Fatalf("list volume flavors: got %+v, %v", flavors, err)
+	}
+	volume, err := cli.CreateVolume(ctx, &model.Volume{Name: "vol1", Capacity: 10, FlavorID: FlavorID,
+		LocationID: LocationID, Description: "scratch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.State != constants.VOLUME_STATE_ALLOCATED || volume.Name != "vol1" {
+		t.Errorf("create volume: got %+v", volume)
+	}
+	if got, err := cli.GetVolume(ctx, volume.VolumeID); err != nil || got.Capacity != 10 {
+		t.Errorf("get volume: got %+v, %v", got, err)
+	}
+	s.Steps = 1
+	updated, err := cli.UpdateVolume(ctx, &model.Volume{VolumeID: volume.VolumeID, Capacity: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.PreviousCapacity != 10 || updated.Capacity != 20 || updated.State != constants.VOLUME_STATE_ALLOCATED {
+		t.Errorf("update volume: got %+v", updated)
+	}
+	s.Steps = 0
+	if v, _ := s.Volume(volume.VolumeID); v.Description != "scratch" {
+		t.Errorf("update volume changed the description to %q", v.Description)
+	}
+	if _, err := cli.UpdateVolume(ctx, &model.Volume{VolumeID: volume.VolumeID, Capacity: 5}); model.Category(err) !=
+		model.ErrorValidation {
+		t.Errorf("shrink volume: got %v, want a validation error", err)
+	}
+
+	attachment, err := cli.CreateVolumeAttachment(ctx, &model.VolumeAttachment{Name: "att1",
+		VolumeID: volume.VolumeID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.State != constants.VOLUME_ATTACHMENT_STATE_READY || attachment.FSConfig == nil ||
+		attachment.FSConfig.StorageID != ClusterName {
+		t.Errorf("create volume attachment: got %+v", attachment)
+	}
+	if v, _ := s.Volume(volume.VolumeID); v.State != glmClient.VOLUMESTATE_VISIBLE {
+		t.Errorf("attached volume is %s, want visible", v.State)
+	}
+	if _, err := cli.DeleteVolume(ctx, volume.VolumeID); model.Category(err) != model.ErrorConflict {
+		t.Errorf("delete attached volume: got %v, want a conflict", err)
+	}
+
+	if _, err := cli.DeleteVolumeAttachment(ctx, attachment.AttachmentID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.GetVolumeAttachment(ctx, attachment.AttachmentID); model.Category(err) != model.ErrorNotFound {
+		t.Errorf("get deleted attachment: got %v", err)
+	}
+	deleted, err := cli.DeleteVolume(ctx, volume.VolumeID)
+	if err != nil || deleted.State != constants.STATE_DELETED {
+		t.Fatalf("delete volume: got %+v, %v", deleted, err)
+	}
+	if volumes, err := cli.ListVolumes(ctx); err != nil || len(*volumes) != 0 {
+		t.Errorf("list volumes after delete: got %+v, %v", volumes, err)
+	}
+}
+
+func TestServerDeleteVolumeNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(t)
+	s.Steps = 1
+	s.PurgeDeleted = true
+	cli := newTestClient(t, s, Password)
+	if err := cli.Login(ctx); err != nil {
+		t.Fatal(err)
+	}
+	volume := s.AddVolume("vol1", 1)
+	// the volume is gone before the wait sees it deleted
+	deleted, err := cli.DeleteVolume(ctx, volume.ID)
+	if err != nil || deleted.State != constants.STATE_DELETED || deleted.VolumeID != volume.ID {
+		t.Fatalf("delete volume: got %+v, %v", deleted, err)
+	}
+	if _, ok := s.Volume(volume.ID); ok {
+		t.Error("volume was not purged")
+	}
+}
+
+func TestServerStateTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps int
+		path  string
+		body  interface{}
+		want  []string
+	}{
+		{name: "volume", steps: 2, path: "/volumes",
+			body: glmClient.NewVolume{Name: "vol1", FlavorID: FlavorID, Capacity: 1, LocationID: LocationID},
+			want: []string{"allocating", "allocating", "allocating", "allocated", "allocated"}},
+		{name: "volume without steps", path: "/volumes",
+			body: glmClient.NewVolume{Name: "vol1", FlavorID: FlavorID, Capacity: 1, LocationID: LocationID},
+			want: []string{"allocating", "allocated"}},
+		{name: "attachment", steps: 1, path: "/volume-attachments",
+			body: glmClient.NewVolumeAttachment{Name: "att1", Protocol: glmClient.ProtocolParameters{Protocol: "fuse"}},
+			want: []string{"exporting", "exporting", "attaching", "attaching", "ready", "ready"}},
+		{name: "attachment without steps", path: "/volume-attachments",
+			body: glmClient.NewVolumeAttachment{Name: "att1", Protocol: glmClient.ProtocolParameters{Protocol: "fuse"}},
+			want: []string{"exporting", "ready"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewServer(t)
+			cli := newTestClient(t, s, Password)
+			if err := cli.Login(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if attachment, ok := tc.body.(glmClient.NewVolumeAttachment); ok {
+				attachment.VolumeID = s.AddVolume("vol1", 1).ID
+				tc.body = attachment
+			}
+			s.Steps = tc.steps
+			base := constants.REST_API_VERSION + tc.path
+			var resource struct{ ID, State string }
+			if err := cli.GetTransport().Do(ctx, &glm.Request{Operation: "create", Method: http.MethodPost,
+				Path: base, Body: tc.body}, &resource); err != nil {
+				t.Fatal(err)
+			}
+			states := []string{resource.State}
+			for len(states) < len(tc.want) {
+				if err := cli.GetTransport().Do(ctx, &glm.Request{Operation: "get", Method: http.MethodGet,
+					Path: base + "/" + resource.ID}, &resource); err != nil {
+					t.Fatal(err)
+				}
+				states = append(states, resource.State)
+			}
+			if !reflect.DeepEqual(states, tc.want) {
+				t.Errorf("got states %v, want %v", states, tc.want)
+			}
+		})
+	}
+}
+
+func TestServerSessions(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(t)
+	err := newTestClient(t, s, "wrong").Login(ctx)
+	if model.Category(err) != model.ErrorUnauthorized || client.IsSessionError(err) {
+		t.Errorf("login with a wrong password: got %v", err)
+	}
+
+	cli := newTestClient(t, s, Password)
+	if _, err := cli.ListVolumes(ctx); !client.IsSessionError(err) {
+		t.Errorf("list volumes before login: got %v, want a session error", err)
+	}
+	if err := cli.Login(ctx); err != nil {
+		t.Fatal(err)
+	}
+	volume := s.AddVolume("vol1", 1)
+	if _, err := cli.GetVolume(ctx, volume.ID); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpireSessions()
+	if _, err := cli.GetVolume(ctx, volume.ID); !client.IsSessionError(err) {
+		t.Errorf("get volume with an expired session: got %v, want a session error", err)
+	}
+	if _, err := cli.GetVolume(ctx, "missing"); model.Category(err) != model.ErrorUnauthorized {
+		t.Errorf("got %v, want the session checked first", err)
+	}
+	want := []string{constants.GRANT_PASSWORD_REALM, constants.GRANT_PASSWORD_REALM}
+	if got := s.Grants(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got grants %v, want %v", got, want)
+	}
+}
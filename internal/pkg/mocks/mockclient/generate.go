@@ -0,0 +1,6 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package mockclient
+
+// Mocks of the GLM client. Run make mocks after changing them.
+//go:generate ${GO_MOCKGEN} -destination generated_mockclient.go -package mockclient github.com/hpe-hcss/lh-cdc-singularity/client ClientInterface
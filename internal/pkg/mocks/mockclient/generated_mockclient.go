@@ -0,0 +1,302 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hpe-hcss/lh-cdc-singularity/client (interfaces: ClientInterface)
+
+// Package mockclient is a generated GoMock package.
+package mockclient
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	client "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	glm "github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+)
+
+// MockClientInterface is a mock of ClientInterface interface.
+type MockClientInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientInterfaceMockRecorder
+}
+
+// MockClientInterfaceMockRecorder is the mock recorder for MockClientInterface.
+type MockClientInterfaceMockRecorder struct {
+	mock *MockClientInterface
+}
+
+// NewMockClientInterface creates a new mock instance.
+func NewMockClientInterface(ctrl *gomock.Controller) *MockClientInterface {
+	mock := &MockClientInterface{ctrl: ctrl}
+	mock.recorder = &MockClientInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientInterface) EXPECT() *MockClientInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateVolume mocks base method.
+func (m *MockClientInterface) CreateVolume(arg0 context.Context, arg1 *model.Volume) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolume", arg0, arg1)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVolume indicates an expected call of CreateVolume.
+func (mr *MockClientInterfaceMockRecorder) CreateVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockClientInterface)(nil).CreateVolume), arg0, arg1)
+}
+
+// CreateVolumeAttachment mocks base method.
+func (m *MockClientInterface) CreateVolumeAttachment(arg0 context.Context, arg1 *model.VolumeAttachment) (*model.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolumeAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVolumeAttachment indicates an expected call of CreateVolumeAttachment.
+func (mr *MockClientInterfaceMockRecorder) CreateVolumeAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolumeAttachment", reflect.TypeOf((*MockClientInterface)(nil).CreateVolumeAttachment), arg0, arg1)
+}
+
+// DeleteVolume mocks base method.
+func (m *MockClientInterface) DeleteVolume(arg0 context.Context, arg1 string) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolume", arg0, arg1)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVolume indicates an expected call of DeleteVolume.
+func (mr *MockClientInterfaceMockRecorder) DeleteVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockClientInterface)(nil).DeleteVolume), arg0, arg1)
+}
+
+// DeleteVolumeAttachment mocks base method.
+func (m *MockClientInterface) DeleteVolumeAttachment(arg0 context.Context, arg1 string) (*model.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolumeAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVolumeAttachment indicates an expected call of DeleteVolumeAttachment.
+func (mr *MockClientInterfaceMockRecorder) DeleteVolumeAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolumeAttachment", reflect.TypeOf((*MockClientInterface)(nil).DeleteVolumeAttachment), arg0, arg1)
+}
+
+// GetGlmCredentials mocks base method.
+func (m *MockClientInterface) GetGlmCredentials() (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlmCredentials")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlmCredentials indicates an expected call of GetGlmCredentials.
+func (mr *MockClientInterfaceMockRecorder) GetGlmCredentials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlmCredentials", reflect.TypeOf((*MockClientInterface)(nil).GetGlmCredentials))
+}
+
+// GetSession mocks base method.
+func (m *MockClientInterface) GetSession() (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession")
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockClientInterfaceMockRecorder) GetSession() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockClientInterface)(nil).GetSession))
+}
+
+// GetSessionToken mocks base method.
+func (m *MockClientInterface) GetSessionToken(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionToken indicates an expected call of GetSessionToken.
+func (mr *MockClientInterfaceMockRecorder) GetSessionToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionToken", reflect.TypeOf((*MockClientInterface)(nil).GetSessionToken), arg0)
+}
+
+// GetTransport mocks base method.
+func (m *MockClientInterface) GetTransport() *glm.Transport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransport")
+	ret0, _ := ret[0].(*glm.Transport)
+	return ret0
+}
+
+// GetTransport indicates an expected call of GetTransport.
+func (mr *MockClientInterfaceMockRecorder) GetTransport() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransport", reflect.TypeOf((*MockClientInterface)(nil).GetTransport))
+}
+
+// GetVolume mocks base method.
+func (m *MockClientInterface) GetVolume(arg0 context.Context, arg1 string) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolume", arg0, arg1)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolume indicates an expected call of GetVolume.
+func (mr *MockClientInterfaceMockRecorder) GetVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockClientInterface)(nil).GetVolume), arg0, arg1)
+}
+
+// GetVolumeAttachment mocks base method.
+func (m *MockClientInterface) GetVolumeAttachment(arg0 context.Context, arg1 string) (*model.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeAttachment", arg0, arg1)
+	ret0, _ := ret[0].(*model.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeAttachment indicates an expected call of GetVolumeAttachment.
+func (mr *MockClientInterfaceMockRecorder) GetVolumeAttachment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeAttachment", reflect.TypeOf((*MockClientInterface)(nil).GetVolumeAttachment), arg0, arg1)
+}
+
+// ListVolumeAttachments mocks base method.
+func (m *MockClientInterface) ListVolumeAttachments(arg0 context.Context) (*[]model.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeAttachments", arg0)
+	ret0, _ := ret[0].(*[]model.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeAttachments indicates an expected call of ListVolumeAttachments.
+func (mr *MockClientInterfaceMockRecorder) ListVolumeAttachments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeAttachments", reflect.TypeOf((*MockClientInterface)(nil).ListVolumeAttachments), arg0)
+}
+
+// ListVolumeFlavors mocks base method.
+func (m *MockClientInterface) ListVolumeFlavors(arg0 context.Context) (*[]model.VolumeFlavor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeFlavors", arg0)
+	ret0, _ := ret[0].(*[]model.VolumeFlavor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeFlavors indicates an expected call of ListVolumeFlavors.
+func (mr *MockClientInterfaceMockRecorder) ListVolumeFlavors(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeFlavors", reflect.TypeOf((*MockClientInterface)(nil).ListVolumeFlavors), arg0)
+}
+
+// ListVolumes mocks base method.
+func (m *MockClientInterface) ListVolumes(arg0 context.Context) (*[]model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumes", arg0)
+	ret0, _ := ret[0].(*[]model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumes indicates an expected call of ListVolumes.
+func (mr *MockClientInterfaceMockRecorder) ListVolumes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockClientInterface)(nil).ListVolumes), arg0)
+}
+
+// Login mocks base method.
+func (m *MockClientInterface) Login(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockClientInterfaceMockRecorder) Login(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockClientInterface)(nil).Login), arg0)
+}
+
+// Logout mocks base method.
+func (m *MockClientInterface) Logout(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockClientInterfaceMockRecorder) Logout(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockClientInterface)(nil).Logout), arg0)
+}
+
+// SetWaitOptions mocks base method.
+func (m *MockClientInterface) SetWaitOptions(arg0 model.WaitOptions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWaitOptions", arg0)
+}
+
+// SetWaitOptions indicates an expected call of SetWaitOptions.
+func (mr *MockClientInterfaceMockRecorder) SetWaitOptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWaitOptions", reflect.TypeOf((*MockClientInterface)(nil).SetWaitOptions), arg0)
+}
+
+// UpdateVolume mocks base method.
+func (m *MockClientInterface) UpdateVolume(arg0 context.Context, arg1 *model.Volume) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVolume", arg0, arg1)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVolume indicates an expected call of UpdateVolume.
+func (mr *MockClientInterfaceMockRecorder) UpdateVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolume", reflect.TypeOf((*MockClientInterface)(nil).UpdateVolume), arg0, arg1)
+}
+
+// WaitForVolumeState mocks base method.
+func (m *MockClientInterface) WaitForVolumeState(arg0 context.Context, arg1 string, arg2 client.VolumeState) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForVolumeState", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForVolumeState indicates an expected call of WaitForVolumeState.
+func (mr *MockClientInterfaceMockRecorder) WaitForVolumeState(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForVolumeState", reflect.TypeOf((*MockClientInterface)(nil).WaitForVolumeState), arg0, arg1, arg2)
+}
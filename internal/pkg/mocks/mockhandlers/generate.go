@@ -0,0 +1,6 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package mockhandlers
+
+// Mocks of the command handlers. Run make mocks after changing them.
+//go:generate ${GO_MOCKGEN} -destination generated_mockhandlers.go -package mockhandlers github.com/hpe-hcss/lh-cdc-singularity/handlers CmdHandler,CmdHandlerFactory
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hpe-hcss/lh-cdc-singularity/handlers (interfaces: CmdHandler,CmdHandlerFactory)
+
+// Package mockhandlers is a generated GoMock package.
+package mockhandlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	handlers "github.com/hpe-hcss/lh-cdc-singularity/handlers"
+)
+
+// MockCmdHandler is a mock of CmdHandler interface.
+type MockCmdHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCmdHandlerMockRecorder
+}
+
+// MockCmdHandlerMockRecorder is the mock recorder for MockCmdHandler.
+type MockCmdHandlerMockRecorder struct {
+	mock *MockCmdHandler
+}
+
+// NewMockCmdHandler creates a new mock instance.
+func NewMockCmdHandler(ctrl *gomock.Controller) *MockCmdHandler {
+	mock := &MockCmdHandler{ctrl: ctrl}
+	mock.recorder = &MockCmdHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCmdHandler) EXPECT() *MockCmdHandlerMockRecorder {
+	return m.recorder
+}
+
+// Handle mocks base method.
+func (m *MockCmdHandler) Handle(arg0 context.Context, arg1 map[string]string, arg2 map[string]interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockCmdHandlerMockRecorder) Handle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockCmdHandler)(nil).Handle), arg0, arg1, arg2)
+}
+
+// MockCmdHandlerFactory is a mock of CmdHandlerFactory interface.
+type MockCmdHandlerFactory struct {
+	ctrl     *gomock.Controller
+	recorder *MockCmdHandlerFactoryMockRecorder
+}
+
+// MockCmdHandlerFactoryMockRecorder is the mock recorder for MockCmdHandlerFactory.
+type MockCmdHandlerFactoryMockRecorder struct {
+	mock *MockCmdHandlerFactory
+}
+
+// NewMockCmdHandlerFactory creates a new mock instance.
+func NewMockCmdHandlerFactory(ctrl *gomock.Controller) *MockCmdHandlerFactory {
+	mock := &MockCmdHandlerFactory{ctrl: ctrl}
+	mock.recorder = &MockCmdHandlerFactoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCmdHandlerFactory) EXPECT() *MockCmdHandlerFactoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCmdHandlerFactory) Create(arg0 string, arg1 []string) (handlers.CmdHandler, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(handlers.CmdHandler)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCmdHandlerFactoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCmdHandlerFactory)(nil).Create), arg0, arg1)
+}
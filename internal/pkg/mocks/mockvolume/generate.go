@@ -0,0 +1,6 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package mockvolume
+
+// Mocks of the volume operations. Run make mocks after changing them.
+//go:generate ${GO_MOCKGEN} -destination generated_mockvolume.go -package mockvolume github.com/hpe-hcss/lh-cdc-singularity/handlers/volume VolumeHandler
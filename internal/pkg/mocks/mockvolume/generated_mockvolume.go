@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hpe-hcss/lh-cdc-singularity/handlers/volume (interfaces: VolumeHandler)
+
+// Package mockvolume is a generated GoMock package.
+package mockvolume
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	client "github.com/hpe-hcss/lh-cdc-singularity/client"
+	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+)
+
+// MockVolumeHandler is a mock of VolumeHandler interface.
+type MockVolumeHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockVolumeHandlerMockRecorder
+}
+
+// MockVolumeHandlerMockRecorder is the mock recorder for MockVolumeHandler.
+type MockVolumeHandlerMockRecorder struct {
+	mock *MockVolumeHandler
+}
+
+// NewMockVolumeHandler creates a new mock instance.
+func NewMockVolumeHandler(ctrl *gomock.Controller) *MockVolumeHandler {
+	mock := &MockVolumeHandler{ctrl: ctrl}
+	mock.recorder = &MockVolumeHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVolumeHandler) EXPECT() *MockVolumeHandlerMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockVolumeHandler) Execute(arg0 context.Context, arg1 *model.Volume, arg2 client.ClientInterface) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockVolumeHandlerMockRecorder) Execute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockVolumeHandler)(nil).Execute), arg0, arg1, arg2)
+}
+
+// MakeResource mocks base method.
+func (m *MockVolumeHandler) MakeResource(arg0 string, arg1 map[string]interface{}) (*model.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeResource", arg0, arg1)
+	ret0, _ := ret[0].(*model.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeResource indicates an expected call of MakeResource.
+func (mr *MockVolumeHandlerMockRecorder) MakeResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeResource", reflect.TypeOf((*MockVolumeHandler)(nil).MakeResource), arg0, arg1)
+}
+
+// ValidateResource mocks base method.
+func (m *MockVolumeHandler) ValidateResource(arg0 string, arg1 *model.Volume) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateResource", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateResource indicates an expected call of ValidateResource.
+func (mr *MockVolumeHandlerMockRecorder) ValidateResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateResource", reflect.TypeOf((*MockVolumeHandler)(nil).ValidateResource), arg0, arg1)
+}
@@ -0,0 +1,6 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package mockvolumeattachment
+
+// Mocks of the volume attachment operations. Run make mocks after changing them.
+//go:generate ${GO_MOCKGEN} -destination generated_mockvolumeattachment.go -package mockvolumeattachment github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_attachment VolumeAttachmentHandler
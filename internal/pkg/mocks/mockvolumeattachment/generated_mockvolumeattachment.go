@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_attachment (interfaces: VolumeAttachmentHandler)
+
+// Package mockvolumeattachment is a generated GoMock package.
+package mockvolumeattachment
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	client "github.com/hpe-hcss/lh-cdc-singularity/client"
+	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+)
+
+// MockVolumeAttachmentHandler is a mock of VolumeAttachmentHandler interface.
+type MockVolumeAttachmentHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockVolumeAttachmentHandlerMockRecorder
+}
+
+// MockVolumeAttachmentHandlerMockRecorder is the mock recorder for MockVolumeAttachmentHandler.
+type MockVolumeAttachmentHandlerMockRecorder struct {
+	mock *MockVolumeAttachmentHandler
+}
+
+// NewMockVolumeAttachmentHandler creates a new mock instance.
+func NewMockVolumeAttachmentHandler(ctrl *gomock.Controller) *MockVolumeAttachmentHandler {
+	mock := &MockVolumeAttachmentHandler{ctrl: ctrl}
+	mock.recorder = &MockVolumeAttachmentHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVolumeAttachmentHandler) EXPECT() *MockVolumeAttachmentHandlerMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockVolumeAttachmentHandler) Execute(arg0 context.Context, arg1 *model.VolumeAttachment, arg2 client.ClientInterface) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockVolumeAttachmentHandlerMockRecorder) Execute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockVolumeAttachmentHandler)(nil).Execute), arg0, arg1, arg2)
+}
+
+// MakeResource mocks base method.
+func (m *MockVolumeAttachmentHandler) MakeResource(arg0 string, arg1 map[string]interface{}) (*model.VolumeAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeResource", arg0, arg1)
+	ret0, _ := ret[0].(*model.VolumeAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeResource indicates an expected call of MakeResource.
+func (mr *MockVolumeAttachmentHandlerMockRecorder) MakeResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeResource", reflect.TypeOf((*MockVolumeAttachmentHandler)(nil).MakeResource), arg0, arg1)
+}
+
+// ValidateResource mocks base method.
+func (m *MockVolumeAttachmentHandler) ValidateResource(arg0 string, arg1 *model.VolumeAttachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateResource", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateResource indicates an expected call of ValidateResource.
+func (mr *MockVolumeAttachmentHandlerMockRecorder) ValidateResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateResource", reflect.TypeOf((*MockVolumeAttachmentHandler)(nil).ValidateResource), arg0, arg1)
+}
@@ -0,0 +1,6 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package mockvolumeflavors
+
+// Mocks of the volume flavor operations. Run make mocks after changing them.
+//go:generate ${GO_MOCKGEN} -destination generated_mockvolumeflavors.go -package mockvolumeflavors github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_flavors VolumeFlavorHandler
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/hpe-hcss/lh-cdc-singularity/handlers/volume_flavors (interfaces: VolumeFlavorHandler)
+
+// Package mockvolumeflavors is a generated GoMock package.
+package mockvolumeflavors
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	client "github.com/hpe-hcss/lh-cdc-singularity/client"
+	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+)
+
+// MockVolumeFlavorHandler is a mock of VolumeFlavorHandler interface.
+type MockVolumeFlavorHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockVolumeFlavorHandlerMockRecorder
+}
+
+// MockVolumeFlavorHandlerMockRecorder is the mock recorder for MockVolumeFlavorHandler.
+type MockVolumeFlavorHandlerMockRecorder struct {
+	mock *MockVolumeFlavorHandler
+}
+
+// NewMockVolumeFlavorHandler creates a new mock instance.
+func NewMockVolumeFlavorHandler(ctrl *gomock.Controller) *MockVolumeFlavorHandler {
+	mock := &MockVolumeFlavorHandler{ctrl: ctrl}
+	mock.recorder = &MockVolumeFlavorHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVolumeFlavorHandler) EXPECT() *MockVolumeFlavorHandlerMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockVolumeFlavorHandler) Execute(arg0 context.Context, arg1 *model.VolumeFlavor, arg2 client.ClientInterface) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockVolumeFlavorHandlerMockRecorder) Execute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockVolumeFlavorHandler)(nil).Execute), arg0, arg1, arg2)
+}
+
+// MakeResource mocks base method.
+func (m *MockVolumeFlavorHandler) MakeResource(arg0 string, arg1 map[string]interface{}) (*model.VolumeFlavor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeResource", arg0, arg1)
+	ret0, _ := ret[0].(*model.VolumeFlavor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeResource indicates an expected call of MakeResource.
+func (mr *MockVolumeFlavorHandlerMockRecorder) MakeResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeResource", reflect.TypeOf((*MockVolumeFlavorHandler)(nil).MakeResource), arg0, arg1)
+}
+
+// ValidateResource mocks base method.
+func (m *MockVolumeFlavorHandler) ValidateResource(arg0 string, arg1 *model.VolumeFlavor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateResource", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateResource indicates an expected call of ValidateResource.
+func (mr *MockVolumeFlavorHandlerMockRecorder) ValidateResource(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateResource", reflect.TypeOf((*MockVolumeFlavorHandler)(nil).ValidateResource), arg0, arg1)
+}
@@ -3,16 +3,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	handlers "github.com/hpe-hcss/lh-cdc-singularity/handlers"
 	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	"github.com/hpe-hcss/lh-cdc-singularity/utils"
 	log "github.com/hpe-storage/common-host-libs/logger"
 	"github.com/spf13/cobra"
 	"github.com/sylabs/singularity/pkg/cmdline"
 	pluginapi "github.com/sylabs/singularity/pkg/plugin"
 	clicallback "github.com/sylabs/singularity/pkg/plugin/callback/cli"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 // Plugin is the only variable which a plugin MUST export.
@@ -33,6 +39,7 @@ var Plugin = pluginapi.Plugin{
 		(clicallback.Command)(callbackVolumeCmd),
 		(clicallback.Command)(callbackVolumeAttachmentCmd),
 		(clicallback.Command)(callbackVolumeFlavorCmd),
+		(clicallback.Command)(callbackGLMCmd),
 	},
 }
 
@@ -75,58 +82,114 @@ func callbackVolumeFlavorCmd(manager *cmdline.CommandManager) {
 	})
 }
 
+func callbackGLMCmd(manager *cmdline.CommandManager) {
+	manager.RegisterCmd(&cobra.Command{
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		Use:                   glmUsage,
+		Short:                 "glm",
+		Long:                  "Manages the GLM session used by the plugin commands",
+		Example:               "singularity glm login username=xyz password-stdin=true",
+		Run:                   run,
+		TraverseChildren:      true,
+	})
+}
+
+// cmdHandlerFactory creates the handlers of the commands. Tests replace it to
+// run commands against mock handlers.
+var cmdHandlerFactory handlers.CmdHandlerFactory = &handlers.CmdHandlerFactoryImpl{}
+
 func run(cmd *cobra.Command, args []string) {
+	if err := runCommand(cmd, args); err != nil {
+		os.Exit(model.ExitCode(err))
+	}
+}
+
+// runCommand executes the command and reports its result. Results go to
+// stdout; failures are reported by utils.PrintError, and the error returned
+// decides the exit code of the plugin.
+func runCommand(cmd *cobra.Command, args []string) (err error) {
 	resourceType := cmd.Short
+	operationType := ""
+	if len(args) >= constants.MIN_ARGS_LENGTH {
+		operationType = args[0]
+	}
+	defer func() {
+		if err != nil {
+			utils.PrintError(err, utils.GetFormat(args), resourceType, operationType)
+		}
+	}()
 	if len(args) < constants.MIN_ARGS_LENGTH {
 		log.Errorln("Error: args length is zero")
-		fmt.Println("Error: args length is zero")
-		return
-	}
-	operationType := args[0]
-	if err := log.InitLogging(constants.LOG_FILE, nil, false); err != nil {
-		log.Errorf("Error: InitLogging: %v\n", err)
-		fmt.Printf("Error: %v\n", err)
-		return
+		return model.ValidationError(errors.New("args length is zero"))
 	}
+	argsMap, argsErr := utils.MakeCommand(args[1:])
 
-	log.Infof("Processing command %v %v\n", resourceType, args)
-	defer log.Infof("Processed command %v %v\n", resourceType, args)
-	ch := &handlers.CmdHandlerFactoryImpl{}
+	// plugin.conf errors other than a bad [logging] section are reported
+	// once logging is up, when the GLM section is read.
+	glm := &model.GLMCredDetails{}
+	loggingDetails, _ := glm.GetLoggingDetails()
+	logSettings, err := utils.NewLogSettings(loggingDetails, argsMap)
+	if err != nil {
+		return err
+	}
+	if err := utils.InitLogging(logSettings); err != nil {
+		return err
+	}
 
-	cmdHandler, err := ch.Create(resourceType, args)
+	log.Infof("Processing command %v %v\n", resourceType, redact.Args(args))
+	defer log.Infof("Processed command %v %v\n", resourceType, redact.Args(args))
+	cmdHandler, err := cmdHandlerFactory.Create(resourceType, args)
 	if err != nil {
 		log.Errorf("Error: %v\n", err)
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
-	glm := &model.GLMCredDetails{}
 	glmCredDetails, err := glm.GetGLMCredDetails()
 	if err != nil {
 		log.Errorln(err)
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
-	argsMap, err := utils.MakeCommand(args[1:])
+	if argsErr != nil {
+		log.Errorln(argsErr)
+		return model.ValidationError(argsErr)
+	}
+	// trace= on the command line takes precedence over plugin.conf
+	if trace, ok := argsMap[constants.TRACE]; ok {
+		glmCredDetails[constants.TRACE] = fmt.Sprint(trace)
+	}
+	timeout, err := utils.GetTimeout(argsMap)
 	if err != nil {
 		log.Errorln(err)
-		fmt.Printf("Error: Response is %v\n", err)
-		return
+		return model.ValidationError(err)
+	}
+	// Ctrl-C, a batch system time limit (SIGTERM) and timeout= all cancel the
+	// requests in flight and stop waiting for GLM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 	//handler a pointer to interface having parse,validate, execute
-	resp, err := cmdHandler.Handle(glmCredDetails, argsMap)
+	resp, err := cmdHandler.Handle(ctx, glmCredDetails, argsMap)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		err = model.NewError(model.ErrorTimeout,
+			fmt.Errorf("operation did not complete within %s=%v", constants.TIMEOUT, timeout))
+	} else if err != nil && ctx.Err() == context.Canceled {
+		err = model.NewError(model.ErrorInterrupted, errors.New("operation interrupted"))
+	}
 	if err != nil {
-		log.Errorf("Error: %v", err)
-		fmt.Printf("Error: %v\n", err)
-		return
+		log.Errorf("Error: %v (exit code %d)", err, model.ExitCode(err))
+		return err
 	}
 	if resp == nil {
 		log.Errorf("Error: %v", resp)
-		fmt.Printf("Error: %v", resp)
-		return
+		return errors.New("empty response")
 	}
-	log.Infof("Response %v", resp)
+	log.Infof("Response %v", redact.Value(resp))
 	var format interface{}
 	format = constants.FORMAT_TABLE
 	if val, ok := argsMap[constants.FORMAT_KEY]; ok {
@@ -137,8 +200,8 @@ func run(cmd *cobra.Command, args []string) {
 	err = formatter.PrintOutput(resp, resourceType, operationType)
 	if err != nil {
 		log.Errorln(err)
-		fmt.Printf("Error: PrintOutput error is %v\n", err)
-		return
+		return err
 	}
-	log.Infof("Command '%v %v' successfully performed\n", resourceType, args)
+	log.Infof("Command '%v %v' successfully performed\n", resourceType, redact.Args(args))
+	return nil
 }
@@ -0,0 +1,723 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/client"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glm"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/glmtest"
+	"github.com/hpe-hcss/lh-cdc-singularity/internal/pkg/mocks/mockhandlers"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/utils"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testVolumeID     = "f0907af6-5d60-4459-9077-7dc5fa9d97cb"
+	testAttachID     = "7875a96f-6582-410c-8689-047bcfc23745"
+	testFlavorID     = "0344e238-5a04-4310-a7b2-a969b5c7bc03"
+	testMembershipID = "D23C0865-01C2-4401-8280-E3397CBB35B5"
+	testMissingID    = "3b0ec5b4-7a5e-4f43-9d0e-0b1f6d6a1c2e"
+)
+
+// writePluginConf writes a plugin.conf for portal and drops the session cache
+// so that every command has to log in first.
+func writePluginConf(t *testing.T, portal string) {
+	if err := os.Remove(model.SessionCacheFile); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	conf := fmt.Sprintf("[glm_credentials]\nglmPortal=%s\nmembershipId=%s\n", portal, glmtest.MembershipID)
+	if err := ioutil.WriteFile(model.PluginConfFile, []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sessionSecrets returns the secrets a command run against s must not leak:
+// the password, the tokens of the session cached for glmtest.Username and the
+// MapR ticket of ready attachments.
+func sessionSecrets(t *testing.T, s *glmtest.Server) []string {
+	secrets := []string{glmtest.Password, glmtest.Ticket}
+	key := model.SessionKey{Portal: s.URL, Username: glmtest.Username, MembershipID: glmtest.MembershipID}
+	session, err := model.NewSessionCache().Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(secrets, session.SessionToken, session.RefreshToken)
+}
+
+func TestLogDoesNotContainSecrets(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	// the attachment created by the commands below
+	attachmentID := "00000000-0000-4000-8000-000000000003"
+	logFile := filepath.Join(dir, "singularity.log")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("GLM_USERNAME", "")
+	t.Setenv("GLM_PASSWORD", "")
+	t.Setenv("GLM_CREDENTIALS_FILE", filepath.Join(dir, "missing"))
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+
+	credentials := []string{"username=" + glmtest.Username, "password=" + glmtest.Password, "format=json"}
+	commands := []struct {
+		resourceType string
+		args         []string
+	}{
+		{"volume", []string{"create", "name=volume_2", "capacity=1", "location_id=" + glmtest.LocationID,
+			"flavor_name=" + glmtest.FlavorName, "description=first"}},
+		{"volume", []string{"get", "volume_id=" + volumeID}},
+		{"volume", []string{"list"}},
+		// debug=true makes the GLM API client dump requests and responses
+		{"volume", []string{"list", "debug=true"}},
+		{"volume-attachment", []string{"create", "name=myattachment", "volume_id=" + volumeID}},
+		{"volume-attachment", []string{"get", "attachment_id=" + attachmentID}},
+		{"volume-attachment", []string{"list"}},
+		{"volume-attachment", []string{"delete", "attachment_id=" + attachmentID}},
+		{"volume-flavor", []string{"list"}},
+		{"volume", []string{"delete", "volume_id=" + volumeID}},
+	}
+	for _, tc := range commands {
+		name := tc.resourceType + " " + tc.args[0]
+		t.Run(name, func(t *testing.T) {
+			writePluginConf(t, s.URL)
+			if err := os.Truncate(logFile, 0); err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			runCommand(&cobra.Command{Short: tc.resourceType}, append(tc.args, credentials...))
+
+			content, err := ioutil.ReadFile(logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			logText := string(content)
+			if !strings.Contains(logText, "successfully performed") {
+				t.Fatalf("%s did not succeed, log:\n%s", name, logText)
+			}
+			for _, secret := range sessionSecrets(t, s) {
+				if strings.Contains(logText, secret) {
+					t.Errorf("%s logged secret %q", name, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceDoesNotContainSecrets(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	traceFile := filepath.Join(dir, "trace.jsonl")
+	t.Setenv("LOG_FILE", filepath.Join(dir, "singularity.log"))
+	t.Setenv("GLM_TRACE_FILE", "")
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	err := runCommand(&cobra.Command{Short: "volume"}, []string{"get", "volume_id=" + volumeID,
+		"username=" + glmtest.Username, "password=" + glmtest.Password, "trace=" + traceFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(traceFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record glm.TraceRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid trace line %s: %v", line, err)
+		}
+		u, _ := url.Parse(record.URL)
+		paths = append(paths, record.Method+" "+u.Path)
+	}
+	want := []string{"GET /info/authsvcinfo", "POST /oauth/token",
+		"GET /rest/v1/volumes/" + volumeID}
+	if len(paths) < len(want) || !reflect.DeepEqual(paths[:len(want)], want) {
+		t.Errorf("traced %v, want %v first", paths, want)
+	}
+	for _, secret := range sessionSecrets(t, s) {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("trace contains secret %q", secret)
+		}
+	}
+}
+
+func TestGLMSession(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	logFile := filepath.Join(dir, "singularity.log")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+	t.Setenv("GLM_PASSWORD", glmtest.Password)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+	sessionKey := model.SessionKey{Portal: s.URL, Username: glmtest.Username, MembershipID: glmtest.MembershipID}
+
+	runGLM := func(operation string) bool {
+		if err := os.Truncate(logFile, 0); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		runCommand(&cobra.Command{Short: "glm"}, []string{operation})
+		content, err := ioutil.ReadFile(logFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Contains(string(content), "successfully performed")
+	}
+
+	if !runGLM("login") {
+		t.Fatal("glm login failed")
+	}
+	token, err := model.GetSessionToken(context.Background(), sessionKey, nil)
+	if err != nil || token == "" {
+		t.Fatalf("session token after login = %q, %v", token, err)
+	}
+	if !runGLM("whoami") {
+		t.Fatal("glm whoami failed after login")
+	}
+	cli, err := client.NewClientFromConfig(map[string]string{constants.GLM_PORTAL: s.URL,
+		constants.MEMBERSHIP_ID: glmtest.MembershipID}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := cli.GetSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Username != glmtest.Username || session.ExpiresAt.After(time.Now().Add(glmtest.TokenLifetime)) ||
+		session.State != model.SessionStateActive {
+		t.Errorf("session = %+v", session)
+	}
+
+	if !runGLM("logout") {
+		t.Fatal("glm logout failed")
+	}
+	if _, err := model.GetSessionToken(context.Background(), sessionKey, nil); err != model.TokenError {
+		t.Errorf("session token still cached after logout, err = %v", err)
+	}
+	if runGLM("whoami") {
+		t.Error("glm whoami succeeded after logout")
+	}
+	if !runGLM("logout") {
+		t.Error("glm logout is not idempotent")
+	}
+	info, err := os.Stat(model.SessionCacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("session cache mode = %v, want 0600", info.Mode().Perm())
+	}
+	conf, err := ioutil.ReadFile(model.PluginConfFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(conf), "sessionToken") {
+		t.Error("session token written to plugin.conf")
+	}
+}
+
+func TestSessionRefreshedBeforeExpiry(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	logFile := filepath.Join(dir, "singularity.log")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+	t.Setenv("GLM_PASSWORD", glmtest.Password)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	cli, err := client.NewClientFromConfig(s.CredDetails(), glmtest.Username, glmtest.Password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Login(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// a session that expires in a minute; the fake portal rejects its token
+	key := model.SessionKey{Portal: s.URL, Username: glmtest.Username, MembershipID: glmtest.MembershipID}
+	expiring, err := model.NewSessionCache().Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiring.ExpiresAt = time.Now().Add(time.Minute)
+	if err := model.NewSessionCache().Put(*expiring); err != nil {
+		t.Fatal(err)
+	}
+	s.ExpireSessions()
+
+	runCommand(&cobra.Command{Short: "volume"}, []string{"get", "volume_id=" + volumeID})
+
+	content, err := ioutil.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "successfully performed") {
+		t.Fatalf("volume get did not succeed, log:\n%s", content)
+	}
+	want := []string{constants.GRANT_PASSWORD_REALM, constants.GRANT_REFRESH_TOKEN}
+	if grants := s.Grants(); !reflect.DeepEqual(grants, want) {
+		t.Errorf("token grants = %v, want %v", grants, want)
+	}
+	session, err := model.NewSessionCache().Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.SessionToken == expiring.SessionToken || !session.ExpiresAt.After(expiring.ExpiresAt) {
+		t.Errorf("session not refreshed: expires at %v", session.ExpiresAt)
+	}
+}
+
+func TestConcurrentLoginsKeepSessionCacheConsistent(t *testing.T) {
+	const (
+		logins = 32
+		rounds = 5
+	)
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	t.Setenv("LOG_FILE", filepath.Join(dir, "singularity.log"))
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "cache", "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	glmCredDetails, err := (&model.GLMCredDetails{}).GetGLMCredDetails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, logins)
+	for i := 0; i < logins; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// the fake portal has one user, the sessions are kept apart by
+			// their membership
+			membershipID := fmt.Sprintf("membership-%02d", i)
+			cli, err := client.NewClientFromConfig(map[string]string{
+				constants.GLM_PORTAL:    glmCredDetails[constants.GLM_PORTAL],
+				constants.MEMBERSHIP_ID: membershipID,
+			}, glmtest.Username, glmtest.Password)
+			for round := 0; round < rounds && err == nil; round++ {
+				if err = cli.Login(ctx); err == nil {
+					_, err = cli.GetSessionToken(ctx)
+				}
+			}
+			if err != nil {
+				errs <- fmt.Errorf("%s: %v", membershipID, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	data, err := ioutil.ReadFile(model.SessionCacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content struct {
+		Sessions []model.CachedSession `json:"sessions"`
+	}
+	if err := json.Unmarshal(data, &content); err != nil {
+		t.Fatalf("session cache is not valid JSON: %v\n%s", err, data)
+	}
+	if len(content.Sessions) != logins {
+		t.Errorf("session cache holds %d sessions, want %d", len(content.Sessions), logins)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "cache", "*.tmp"))
+	if len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+	if _, err := (&model.GLMCredDetails{}).GetGLMCredDetails(); err != nil {
+		t.Errorf("plugin.conf no longer parses: %v", err)
+	}
+}
+
+func TestTimeoutStopsWaiting(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	logFile := filepath.Join(dir, "singularity.log")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+	t.Setenv("GLM_PASSWORD", glmtest.Password)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	start := time.Now()
+	// the fake volume stays allocated, it never becomes visible
+	runCommand(&cobra.Command{Short: "volume"}, []string{"wait", "volume_id=" + volumeID, "state=visible", "timeout=1s"})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("volume wait with timeout=1s took %v", elapsed)
+	}
+	content, err := ioutil.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "operation did not complete within timeout=1s") {
+		t.Errorf("timeout not reported, log:\n%s", content)
+	}
+}
+
+func TestVolumeWait(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	logFile := filepath.Join(dir, "singularity.log")
+	t.Setenv("LOG_FILE", logFile)
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+	t.Setenv("GLM_PASSWORD", glmtest.Password)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"wait", "volume_id=" + volumeID, "state=Allocated"},
+			"Command 'volume [wait volume_id=" + volumeID + " state=Allocated]' successfully performed"},
+		{[]string{"wait", "volume_id=" + volumeID, "state=visible", "wait_timeout=1s"},
+			"did not reach state visible within 1s, current state: allocated"},
+		{[]string{"wait", "volume_id=" + volumeID, "state=ready"},
+			"invalid value of state is provided"},
+		{[]string{"delete", "volume_id=" + volumeID, "wait=false"},
+			"State:deleting"},
+		{[]string{"get", "volume_id=" + volumeID, "wait_timeout=soon"},
+			"argument 'wait_timeout' value 'soon' is not a duration"},
+	}
+	for _, test := range tests {
+		if err := os.Truncate(logFile, 0); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		runCommand(&cobra.Command{Short: "volume"}, test.args)
+		content, err := ioutil.ReadFile(logFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), test.want) {
+			t.Errorf("volume %v: %q not logged, log:\n%s", test.args, test.want, content)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	t.Setenv("LOG_FILE", filepath.Join(dir, "singularity.log"))
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+
+	tests := []struct {
+		name     string
+		password string
+		args     []string
+		want     int
+	}{
+		{"success", glmtest.Password, []string{"get", "volume_id=" + volumeID}, model.ExitOK},
+		{"unknown argument", glmtest.Password, []string{"get", "volume_id=" + volumeID, "size=1"},
+			model.ExitValidation},
+		{"missing volume", glmtest.Password, []string{"get", "volume_id=" + testMissingID}, model.ExitNotFound},
+		{"wrong password", "wrong", []string{"get", "volume_id=" + volumeID}, model.ExitUnauthorized},
+		{"wait timeout", glmtest.Password,
+			[]string{"wait", "volume_id=" + volumeID, "state=visible", "wait_timeout=1s"}, model.ExitTimeout},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GLM_PASSWORD", test.password)
+			writePluginConf(t, s.URL)
+			err := runCommand(&cobra.Command{Short: "volume"}, test.args)
+			if got := model.ExitCode(err); got != test.want {
+				t.Errorf("exit code %d (%v), want %d", got, err, test.want)
+			}
+		})
+	}
+
+	t.Run("portal unreachable", func(t *testing.T) {
+		t.Setenv("GLM_PASSWORD", glmtest.Password)
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		writePluginConf(t, closed.URL)
+		err := runCommand(&cobra.Command{Short: "volume"}, []string{"get", "volume_id=" + volumeID})
+		if got := model.ExitCode(err); got != model.ExitTransport {
+			t.Errorf("exit code %d (%v), want %d", got, err, model.ExitTransport)
+		}
+	})
+}
+
+// captureOutput returns what fn writes to stdout and stderr.
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	savedStdout, savedStderr := os.Stdout, os.Stderr
+	defer func() { os.Stdout, os.Stderr = savedStdout, savedStderr }()
+	var outputs [2]string
+	var wg sync.WaitGroup
+	files := make([]*os.File, 2)
+	for i := range files {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[i] = w
+		wg.Add(1)
+		go func(i int, r *os.File) {
+			defer wg.Done()
+			data, _ := ioutil.ReadAll(r)
+			outputs[i] = string(data)
+		}(i, r)
+	}
+	os.Stdout, os.Stderr = files[0], files[1]
+	fn()
+	files[0].Close()
+	files[1].Close()
+	wg.Wait()
+	return outputs[0], outputs[1]
+}
+
+func TestJSONErrorOutput(t *testing.T) {
+	dir := t.TempDir()
+	s := glmtest.NewServer(t)
+	volumeID := s.AddVolume("volume_1", 1).ID
+	t.Setenv("LOG_FILE", filepath.Join(dir, "singularity.log"))
+	t.Setenv("GLM_USERNAME", glmtest.Username)
+	t.Setenv("GLM_PASSWORD", glmtest.Password)
+
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, s.URL)
+
+	stdout, stderr := captureOutput(t, func() {
+		runCommand(&cobra.Command{Short: "volume"}, []string{"get", "volume_id=" + testMissingID, "format=json"})
+	})
+	if stdout != "" {
+		t.Errorf("stdout is not empty: %q", stdout)
+	}
+	var got utils.ErrorOutput
+	if err := json.Unmarshal([]byte(stderr), &got); err != nil {
+		t.Fatalf("stderr is not a JSON error object: %v: %q", err, stderr)
+	}
+	want := utils.ErrorOutput{Code: model.ExitNotFound, Category: "not-found",
+		Message: "get volume failed: volume not found (HTTP 404)", ResourceType: "volume", Operation: "get",
+		StatusCode: http.StatusNotFound, RequestID: glmtest.RequestID}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// argument errors are reported in JSON too
+	_, stderr = captureOutput(t, func() {
+		runCommand(&cobra.Command{Short: "volume"}, []string{"get", "format=json", "volume_id"})
+	})
+	got = utils.ErrorOutput{}
+	if err := json.Unmarshal([]byte(stderr), &got); err != nil || got.Category != "validation" {
+		t.Errorf("argument error not reported as JSON validation error: %q", stderr)
+	}
+
+	stdout, stderr = captureOutput(t, func() {
+		runCommand(&cobra.Command{Short: "volume"}, []string{"get", "volume_id=" + volumeID, "format=json"})
+	})
+	if stderr != "" || !strings.HasPrefix(stdout, "[{") {
+		t.Errorf("unexpected output, stdout: %q, stderr: %q", stdout, stderr)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("LOG_FILE", filepath.Join(dir, "singularity.log"))
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	defer func() { model.PluginConfFile = savedConf }()
+	savedCache := model.SessionCacheFile
+	model.SessionCacheFile = filepath.Join(dir, "sessions.json")
+	defer func() { model.SessionCacheFile = savedCache }()
+	writePluginConf(t, glmtest.NewServer(t).URL)
+
+	volume := &model.Volume{Name: "vol1", VolumeID: testVolumeID, FlavorID: testFlavorID, Capacity: 10,
+		State: glmClient.VOLUMESTATE_ALLOCATED, Status: glmClient.VOLUMESTATUS_OK}
+	attachment := &model.VolumeAttachment{Name: "att1", VolumeID: testVolumeID, AttachmentID: testAttachID,
+		State: glmClient.VASTATEENUM_READY, FSConfig: &glmClient.VafsConfig{StorageID: "cluster1",
+			UserName: "mapr", Ticket: "ticket", TicketExpiryTime: "2100-01-01T00:00:00Z"}}
+	session := &model.Session{Username: "xyz@hpe.com", Portal: "https://glm.example.com",
+		MembershipID: testMembershipID, State: model.SessionStateActive}
+	ids := strings.NewReplacer("$VOLUME", testVolumeID, "$ATTACHMENT", testAttachID, "$FLAVOR", testFlavorID,
+		"$MISSING", testMissingID, "$MEMBERSHIP", testMembershipID)
+	volumeTable := `
+NAME  ID                                    FLAVOR_ID                             CAPACITY  STATUS  STATE
+vol1  $VOLUME  $FLAVOR  10        ok      allocated`
+	volumeJSON := `[{"CAPACITY":"10","FLAVOR_ID":"$FLAVOR","ID":"$VOLUME","NAME":"vol1","STATE":"allocated",` +
+		`"STATUS":"ok"}]`
+	labeled := *volume
+	labeled.Labels = model.Labels{"team": "genomics", "env": "dev"}
+	labeledTable := `
+NAME  ID                                    FLAVOR_ID                             CAPACITY  STATUS  STATE      LABELS
+vol1  $VOLUME  $FLAVOR  10        ok      allocated  env=dev,team=genomics`
+	labeledJSON := `[{"CAPACITY":"10","FLAVOR_ID":"$FLAVOR","ID":"$VOLUME","LABELS":"env=dev,team=genomics",` +
+		`"NAME":"vol1","STATE":"allocated","STATUS":"ok"}]`
+	sessionTable := `
+USERNAME     PORTAL                   MEMBERSHIP_ID                         STATE   EXPIRES_AT
+xyz@hpe.com  https://glm.example.com  $MEMBERSHIP  active  unknown`
+	sessionJSON := `[{"EXPIRES_AT":"unknown","MEMBERSHIP_ID":"$MEMBERSHIP",` +
+		`"PORTAL":"https://glm.example.com","STATE":"active","USERNAME":"xyz@hpe.com"}]`
+
+	tests := []struct {
+		resourceType string
+		args         []string
+		resp         interface{}
+		// table and json are the outputs expected, without the trailing
+		// blanks of table rows
+		table string
+		json  string
+	}{
+		{constants.VOLUME, []string{"create", "name=vol1"}, volume, volumeTable, volumeJSON},
+		{constants.VOLUME, []string{"get"}, &labeled, labeledTable, labeledJSON},
+		{constants.VOLUME, []string{"wait"}, volume, volumeTable, volumeJSON},
+		{constants.VOLUME, []string{"list"}, &[]model.Volume{*volume, {Name: "vol2", VolumeID: testMissingID,
+			MountPath: "/mapr/cluster1/cdc-vol"}}, `
+NAME  ID                                    MOUNT_PATH
+vol1  $VOLUME
+vol2  $MISSING  /mapr/cluster1/cdc-vol`,
+			`[{"ID":"$VOLUME","MOUNT_PATH":"","NAME":"vol1"},` +
+				`{"ID":"$MISSING","MOUNT_PATH":"/mapr/cluster1/cdc-vol","NAME":"vol2"}]`},
+		{constants.VOLUME, []string{"update"}, &model.Volume{Name: "vol1", VolumeID: testVolumeID, Capacity: 20,
+			PreviousCapacity: 10, State: glmClient.VOLUMESTATE_ALLOCATED}, `
+NAME  ID                                    PREVIOUS_CAPACITY  CAPACITY  STATE
+vol1  $VOLUME  10                 20        allocated`,
+			`[{"CAPACITY":"20","ID":"$VOLUME","NAME":"vol1","PREVIOUS_CAPACITY":"10","STATE":"allocated"}]`},
+		{constants.VOLUME, []string{"delete"}, &model.Volume{VolumeID: testVolumeID, State: constants.STATE_DELETED}, `
+ID                                    STATE
+$VOLUME  deleted`,
+			`[{"ID":"$VOLUME","STATE":"deleted"}]`},
+		{constants.VOLUME_ATTACHMENT, []string{"create"}, attachment, `
+NAME  ID                                    VOLUME_ID                             STATE
+att1  $ATTACHMENT  $VOLUME  ready`,
+			`[{"ID":"$ATTACHMENT","NAME":"att1","STATE":"ready","VOLUME_ID":"$VOLUME"}]`},
+		{constants.VOLUME_ATTACHMENT, []string{"get"}, attachment, `
+NAME  ID                                    VOLUME_ID                             STATE  STORAGE_ID  USER_NAME  TICKET  TICKET_EXPIRY_TIME
+att1  $ATTACHMENT  $VOLUME  ready  cluster1    mapr       ticket  2100-01-01T00:00:00Z`,
+			`[{"ID":"$ATTACHMENT","NAME":"att1","STATE":"ready","STORAGE_ID":"cluster1","TICKET":"ticket",` +
+				`"TICKET_EXPIRY_TIME":"2100-01-01T00:00:00Z","USER_NAME":"mapr","VOLUME_ID":"$VOLUME"}]`},
+		{constants.VOLUME_ATTACHMENT, []string{"list"}, &[]model.VolumeAttachment{*attachment}, `
+NAME  ID                                    VOLUME_ID                             STATE
+att1  $ATTACHMENT  $VOLUME  ready`,
+			`[{"ID":"$ATTACHMENT","NAME":"att1","STATE":"ready","VOLUME_ID":"$VOLUME"}]`},
+		{constants.VOLUME_ATTACHMENT, []string{"delete"}, &model.VolumeAttachment{AttachmentID: testAttachID,
+			State: glmClient.VASTATEENUM_DELETED}, `
+ID                                    STATE
+$ATTACHMENT  deleted`,
+			`[{"ID":"$ATTACHMENT","STATE":"deleted"}]`},
+		{constants.VOLUME_FLAVORS, []string{"list"}, &[]model.VolumeFlavor{{ID: testFlavorID, Name: "Default"}}, `
+NAME     ID
+Default  $FLAVOR`,
+			`[{"ID":"$FLAVOR","NAME":"Default"}]`},
+		{constants.GLM, []string{"login"}, session, sessionTable, sessionJSON},
+		{constants.GLM, []string{"whoami"}, session, sessionTable, sessionJSON},
+		{constants.GLM, []string{"logout"}, &model.Session{Portal: "https://glm.example.com",
+			State: model.SessionStateLoggedOut}, `
+PORTAL                   STATE
+https://glm.example.com  logged out`,
+			`[{"PORTAL":"https://glm.example.com","STATE":"logged out"}]`},
+	}
+	for _, tc := range tests {
+		for format, want := range map[string]string{constants.FORMAT_TABLE: tc.table, "json": tc.json} {
+			t.Run(tc.resourceType+" "+tc.args[0]+" "+format, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				factory := mockhandlers.NewMockCmdHandlerFactory(ctrl)
+				handler := mockhandlers.NewMockCmdHandler(ctrl)
+				args := append(append([]string{}, tc.args...), "format="+format)
+				argsMap, err := utils.MakeCommand(args[1:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				factory.EXPECT().Create(tc.resourceType, args).Return(handler, nil)
+				handler.EXPECT().Handle(gomock.Any(), gomock.Any(), argsMap).Return(tc.resp, nil)
+				saved := cmdHandlerFactory
+				cmdHandlerFactory = factory
+				defer func() { cmdHandlerFactory = saved }()
+
+				stdout, stderr := captureOutput(t, func() {
+					if err := runCommand(&cobra.Command{Short: tc.resourceType}, args); err != nil {
+						t.Error(err)
+					}
+				})
+				lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+				for i := range lines {
+					lines[i] = strings.TrimRight(lines[i], " ")
+				}
+				want = strings.TrimPrefix(ids.Replace(want), "\n")
+				if got := strings.Join(lines, "\n"); got != want || stderr != "" {
+					t.Errorf("got\n%s\nwant\n%s\nstderr: %q", got, want, stderr)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorCategory classifies the errors reported by the plugin. Every category
+// maps to a documented process exit code, so that scripts can branch on it.
+type ErrorCategory string
+
+const (
+	ErrorNotFound     ErrorCategory = "not-found"
+	ErrorUnauthorized ErrorCategory = "unauthorized"
+	ErrorConflict     ErrorCategory = "conflict"
+	ErrorQuota        ErrorCategory = "quota"
+	ErrorTimeout      ErrorCategory = "timeout"
+	ErrorTransport    ErrorCategory = "transport"
+	ErrorValidation   ErrorCategory = "validation"
+	ErrorInterrupted  ErrorCategory = "interrupted"
+	ErrorInternal     ErrorCategory = "internal"
+)
+
+// Process exit codes. They are part of the plugin interface; see the README.
+const (
+	ExitOK           = 0
+	ExitInternal     = 1
+	ExitValidation   = 2
+	ExitUnauthorized = 3
+	ExitNotFound     = 4
+	ExitConflict     = 5
+	ExitQuota        = 6
+	ExitTimeout      = 7
+	ExitTransport    = 8
+	ExitInterrupted  = 130
+)
+
+var exitCodes = map[ErrorCategory]int{
+	ErrorNotFound:     ExitNotFound,
+	ErrorUnauthorized: ExitUnauthorized,
+	ErrorConflict:     ExitConflict,
+	ErrorQuota:        ExitQuota,
+	ErrorTimeout:      ExitTimeout,
+	ErrorTransport:    ExitTransport,
+	ErrorValidation:   ExitValidation,
+	ErrorInterrupted:  ExitInterrupted,
+	ErrorInternal:     ExitInternal,
+}
+
+// APIError is an error with a category, and for errors returned by GLM the
+// HTTP status, the message of the server and the ID GLM gave the request.
+type APIError struct {
+	Category   ErrorCategory
+	StatusCode int
+	Message    string
+	RequestID  string
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+	}
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// NewError returns an error of category with the message err, keeping err
+// available to errors.Is and errors.As.
+func NewError(category ErrorCategory, err error) *APIError {
+	return &APIError{Category: category, Message: err.Error(), Err: err}
+}
+
+// ValidationError marks err as a problem with the command line or its
+// arguments.
+func ValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	return NewError(ErrorValidation, err)
+}
+
+// NewStatusError describes a failed GLM request. operation says what failed,
+// e.g. "get volume", and body is the response body the message is taken from.
+func NewStatusError(operation string, statusCode int, body []byte) *APIError {
+	message := ServerMessage(body)
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &APIError{
+		Category:   CategoryForStatus(statusCode, message),
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("%s failed: %s", operation, message),
+	}
+}
+
+// ServerMessage extracts the error message from a GLM response body. GLM
+// answers with {"message": ..., "details": ...}; plain text bodies are
+// returned trimmed.
+func ServerMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+		Details string `json:"details"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Message != "" {
+		if resp.Details != "" {
+			return resp.Message + ": " + resp.Details
+		}
+		return resp.Message
+	}
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "<") {
+		return ""
+	}
+	return text
+}
+
+// CategoryForStatus classifies an HTTP error status. GLM reports exhausted
+// quotas with several statuses, so the message is looked at too.
+func CategoryForStatus(statusCode int, message string) ErrorCategory {
+	if strings.Contains(strings.ToLower(message), "quota") {
+		return ErrorQuota
+	}
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrorValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorUnauthorized
+	case http.StatusNotFound, http.StatusGone:
+		return ErrorNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ErrorConflict
+	case http.StatusTooManyRequests, http.StatusInsufficientStorage, http.StatusRequestEntityTooLarge:
+		return ErrorQuota
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrorTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrorTransport
+	}
+	return ErrorInternal
+}
+
+// Category returns the category of err. Errors without one are internal.
+func Category(err error) ErrorCategory {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Category
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorInterrupted
+	case errors.Is(err, TokenError):
+		return ErrorUnauthorized
+	}
+	return ErrorInternal
+}
+
+// ExitCode returns the process exit code for err, 0 when err is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return exitCodes[Category(err)]
+}
@@ -0,0 +1,47 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("plain"), ExitInternal},
+		{NewStatusError("get volume", 404, []byte(`{"message":"volume not found"}`)), ExitNotFound},
+		{NewStatusError("get OAUTH token", 403, []byte("bad credentials")), ExitUnauthorized},
+		{NewStatusError("create volume", 409, nil), ExitConflict},
+		{NewStatusError("create volume", 400, []byte(`{"message":"storage quota exceeded"}`)), ExitQuota},
+		{NewStatusError("list volumes", 503, []byte("<html>unavailable</html>")), ExitTransport},
+		{NewStatusError("list volumes", 500, nil), ExitInternal},
+		{ValidationError(errors.New("Invalid argument size")), ExitValidation},
+		{fmt.Errorf("Session creation failed with error: %w", NewStatusError("login", 401, nil)), ExitUnauthorized},
+		{fmt.Errorf("waiting: %w", context.DeadlineExceeded), ExitTimeout},
+		{context.Canceled, ExitInterrupted},
+		{TokenError, ExitUnauthorized},
+	}
+	for _, test := range tests {
+		if got := ExitCode(test.err); got != test.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", test.err, got, test.want)
+		}
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	err := NewStatusError("get volume", 404, []byte(`{"message":"volume not found","details":"id abc"}`))
+	if want := "get volume failed: volume not found: id abc (HTTP 404)"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+	err = NewStatusError("list volumes", 502, []byte("<html>bad gateway</html>"))
+	if want := "list volumes failed: Bad Gateway (HTTP 502)"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
@@ -3,11 +3,13 @@
 package model
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	log "github.com/hpe-storage/common-host-libs/logger"
 	"gopkg.in/ini.v1"
+	"time"
 )
 
 type GLMCredDetails struct{}
@@ -31,55 +33,102 @@ type AuthRequest struct {
 	Realm     string `json:"realm"`
 }
 
+type RefreshRequest struct {
+	GrantType    string `json:"grant_type"`
+	ClientID     string `json:"client_id"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 type AuthResponse struct {
-	IDToken     string `json:"id_token"`
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
+	IDToken      string `json:"id_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
 }
 
-var TokenError = errors.New("session token is not present in plugin.conf file")
+// SessionRefresher renews a cached session that is about to expire.
+type SessionRefresher interface {
+	RefreshSession(ctx context.Context, session *CachedSession) (*CachedSession, error)
+}
+
+var TokenError = errors.New("no GLM session is cached for the user")
+
+// PluginConfFile is the plugin configuration file. Tests point it at a
+// temporary file.
+var PluginConfFile = constants.PLUGIN_CONF_FILE
 
 func (glm *GLMCredDetails) GetGLMCredDetails() (map[string]string, error) {
-	cfg, err := ini.Load(constants.PLUGIN_CONF_FILE)
+	glmCredDetails, err := readSection(constants.GLM_CREDENTIALS)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read configuration file '%s'", constants.PLUGIN_CONF_FILE)
+		return nil, err
 	}
+	if glmCredDetails == nil {
+		return nil, fmt.Errorf("GLM section %s not configured in plugin.conf file", constants.GLM_CREDENTIALS)
+	}
+	return glmCredDetails, nil
+}
 
-	glmSection, err := cfg.GetSection(constants.GLM_CREDENTIALS)
+// GetLoggingDetails returns the optional [logging] section of plugin.conf. A
+// missing section yields an empty map.
+func (glm *GLMCredDetails) GetLoggingDetails() (map[string]string, error) {
+	loggingDetails, err := readSection(constants.LOGGING)
 	if err != nil {
-		return nil, fmt.Errorf("GLM section %s not configured in plugin.conf file", constants.GLM_CREDENTIALS)
+		return nil, err
 	}
-	glmCredDetails := map[string]string{}
-	keys := glmSection.Keys()
-	for _, key := range keys {
-		glmCredDetails[key.Name()] = key.Value()
+	if loggingDetails == nil {
+		loggingDetails = map[string]string{}
 	}
-	return glmCredDetails, nil
+	return loggingDetails, nil
 }
 
-func (glm *GLMCredDetails) UpdateGLMCredentials(key string, value string) error {
-	cfg, err := ini.Load(constants.PLUGIN_CONF_FILE)
+// readSection returns the keys of a plugin.conf section, or nil when the
+// section does not exist.
+func readSection(name string) (map[string]string, error) {
+	data, err := readFileShared(PluginConfFile)
 	if err != nil {
-		return fmt.Errorf("failed to read configuration file '%s'", constants.PLUGIN_CONF_FILE)
+		return nil, fmt.Errorf("failed to read configuration file '%s'", PluginConfFile)
 	}
-	cfg.Section(constants.GLM_CREDENTIALS).Key(key).SetValue(value)
-	err = cfg.SaveTo(constants.PLUGIN_CONF_FILE)
+	cfg, err := ini.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file '%s'", PluginConfFile)
+	}
+
+	section, err := cfg.GetSection(name)
 	if err != nil {
-		return fmt.Errorf("failed to save contents in configuration file '%s'", constants.PLUGIN_CONF_FILE)
+		return nil, nil
 	}
-	return nil
+	details := map[string]string{}
+	keys := section.Keys()
+	for _, key := range keys {
+		details[key.Name()] = key.Value()
+	}
+	return details, nil
 }
 
-func GetSessionToken() (string, error) {
-	glm := &GLMCredDetails{}
-	glmCredDetails, err := glm.GetGLMCredDetails()
+// GetSessionToken returns the session token cached for key. A token that
+// expires within TOKEN_REFRESH_MARGIN is renewed through refresher first, so
+// that a command does not fail halfway with a stale token. It returns
+// TokenError when the user has to log in first.
+func GetSessionToken(ctx context.Context, key SessionKey, refresher SessionRefresher) (string, error) {
+	session, err := NewSessionCache().Get(key)
 	if err != nil {
 		log.Errorln(err)
 		return "", err
 	}
-	if glmCredDetails[constants.SESSION_TOKEN] == "" {
-		log.Errorln("session token is not present in plugin.conf file")
+	if !session.ExpiresWithin(constants.TOKEN_REFRESH_MARGIN * time.Second) {
+		return session.SessionToken, nil
+	}
+	log.Infof("GLM session expires at %v, refreshing it", session.ExpiresAt)
+	if refresher != nil {
+		refreshed, err := refresher.RefreshSession(ctx, session)
+		if err == nil {
+			return refreshed.SessionToken, nil
+		}
+		log.Warnf("GLM session refresh failed: %v", err)
+	}
+	if session.ExpiresWithin(0) {
 		return "", TokenError
 	}
-	return glmCredDetails[constants.SESSION_TOKEN], nil
+	return session.SessionToken, nil
 }
@@ -0,0 +1,164 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Labels are key=value pairs telling which project or job a volume belongs
+// to. GLM volumes have no metadata, so the labels are kept at the end of the
+// volume description, e.g. "scratch [labels:env=dev,team=genomics]".
+type Labels map[string]string
+
+const (
+	labelsPrefix = "[labels:"
+	labelsSuffix = "]"
+)
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^[A-Za-z0-9._-]*$`)
+)
+
+// ParseLabels parses labels given as key=value,key=value. An empty string
+// gives no labels.
+func ParseLabels(s string) (Labels, error) {
+	labels := Labels{}
+	if strings.TrimSpace(s) == "" {
+		return labels, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		tokens := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("label %q is not of the form key=value", pair)
+		}
+		key, value := tokens[0], tokens[1]
+		if err := validateLabel(key, value); err != nil {
+			return nil, err
+		}
+		if _, ok := labels[key]; ok {
+			return nil, fmt.Errorf("label %s is given twice", key)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+func validateLabel(key, value string) error {
+	if !labelKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid label key %q, keys are letters, digits and . _ / - inside", key)
+	}
+	if !labelValuePattern.MatchString(value) {
+		return fmt.Errorf("invalid value %q of label %s, values are letters, digits, . _ and -", value, key)
+	}
+	return nil
+}
+
+// String returns the labels as key=value,key=value sorted by key.
+func (labels Labels) String() string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// EncodeDescription returns the GLM description of a volume with description
+// and labels.
+func EncodeDescription(description string, labels Labels) string {
+	if len(labels) == 0 {
+		return description
+	}
+	encoded := labelsPrefix + labels.String() + labelsSuffix
+	if description == "" {
+		return encoded
+	}
+	return description + " " + encoded
+}
+
+// DecodeDescription splits the GLM description of a volume into the
+// description and the labels. A description without valid labels is returned
+// as it is.
+func DecodeDescription(encoded string) (string, Labels) {
+	i := strings.LastIndex(encoded, labelsPrefix)
+	if i < 0 || !strings.HasSuffix(encoded, labelsSuffix) {
+		return encoded, nil
+	}
+	labels, err := ParseLabels(encoded[i+len(labelsPrefix) : len(encoded)-len(labelsSuffix)])
+	if err != nil {
+		return encoded, nil
+	}
+	return strings.TrimSuffix(encoded[:i], " "), labels
+}
+
+// Selector selects volumes by their labels. Every requirement must hold.
+type Selector []labelRequirement
+
+type labelRequirement struct {
+	key   string
+	value string
+	// one of "=", "!=", "exists" and "!exists"
+	operator string
+}
+
+// ParseSelector parses a comma separated list of requirements: key=value (or
+// key==value), key!=value, key, which requires the label, and !key, which
+// requires its absence.
+func ParseSelector(s string) (Selector, error) {
+	selector := Selector{}
+	if strings.TrimSpace(s) == "" {
+		return selector, nil
+	}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		var req labelRequirement
+		switch {
+		case strings.HasPrefix(term, "!"):
+			req = labelRequirement{key: term[1:], operator: "!exists"}
+		case strings.Contains(term, "!="):
+			tokens := strings.SplitN(term, "!=", 2)
+			req = labelRequirement{key: tokens[0], value: tokens[1], operator: "!="}
+		case strings.Contains(term, "="):
+			tokens := strings.SplitN(term, "=", 2)
+			req = labelRequirement{key: tokens[0], value: strings.TrimPrefix(tokens[1], "="), operator: "="}
+		default:
+			req = labelRequirement{key: term, operator: "exists"}
+		}
+		if err := validateLabel(req.key, req.value); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %v", term, err)
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+// Matches tells whether labels meet every requirement of the selector.
+func (selector Selector) Matches(labels Labels) bool {
+	for _, req := range selector {
+		value, ok := labels[req.key]
+		switch req.operator {
+		case "=":
+			if !ok || value != req.value {
+				return false
+			}
+		case "!=":
+			if ok && value == req.value {
+				return false
+			}
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		s         string
+		want      Labels
+		wantError bool
+	}{
+		{"", Labels{}, false},
+		{"team=genomics", Labels{"team": "genomics"}, false},
+		{"team=genomics, env=dev", Labels{"team": "genomics", "env": "dev"}, false},
+		{"hpe.com/project=", Labels{"hpe.com/project": ""}, false},
+		{"team", nil, true},
+		{"=genomics", nil, true},
+		{"team=gen omics", nil, true},
+		{"team=genomics,team=physics", nil, true},
+	}
+	for _, test := range tests {
+		got, err := ParseLabels(test.s)
+		if (err != nil) != test.wantError || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseLabels(%q) = %v, %v, want %v", test.s, got, err, test.want)
+		}
+	}
+}
+
+func TestEncodeDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		labels      Labels
+		encoded     string
+	}{
+		{"scratch", nil, "scratch"},
+		{"scratch", Labels{"team": "genomics", "env": "dev"}, "scratch [labels:env=dev,team=genomics]"},
+		{"", Labels{"team": "genomics"}, "[labels:team=genomics]"},
+	}
+	for _, test := range tests {
+		encoded := EncodeDescription(test.description, test.labels)
+		if encoded != test.encoded {
+			t.Errorf("EncodeDescription(%q, %v) = %q, want %q", test.description, test.labels, encoded,
+				test.encoded)
+		}
+		description, labels := DecodeDescription(encoded)
+		if description != test.description || !reflect.DeepEqual(labels, test.labels) {
+			t.Errorf("DecodeDescription(%q) = %q, %v", encoded, description, labels)
+		}
+	}
+	// descriptions written by others are left as they are
+	for _, encoded := range []string{"copy of [labels]", "scratch [labels:team]"} {
+		if description, labels := DecodeDescription(encoded); description != encoded || labels != nil {
+			t.Errorf("DecodeDescription(%q) = %q, %v", encoded, description, labels)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := Labels{"team": "genomics", "env": "dev"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=genomics", true},
+		{"team==genomics", true},
+		{"team=physics", false},
+		{"team=genomics,env!=prod", true},
+		{"team=genomics,env!=dev", false},
+		{"owner!=ana", true},
+		{"env", true},
+		{"owner", false},
+		{"!owner", true},
+		{"!env", false},
+	}
+	for _, test := range tests {
+		selector, err := ParseSelector(test.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector(%q): %v", test.selector, err)
+		}
+		if got := selector.Matches(labels); got != test.want {
+			t.Errorf("%q matches %v = %t, want %t", test.selector, labels, got, test.want)
+		}
+	}
+	for _, s := range []string{"team=gen omics", "team=genomics,", "!"} {
+		if _, err := ParseSelector(s); err == nil {
+			t.Errorf("ParseSelector(%q) succeeded", s)
+		}
+	}
+}
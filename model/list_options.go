@@ -0,0 +1,225 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListOptions narrow down, order and page the items of a list command. Filters
+// and sort keys name the fields of the items, e.g. name, state or flavor_id.
+// The GLM list endpoints take no query parameters, so the options are applied
+// to the items GLM returns.
+type ListOptions struct {
+	// Filter is a comma separated list of field=pattern and field!=pattern,
+	// where the pattern may use the wildcards * and ?
+	Filter string `json:"filter,omitempty"`
+	// Sort is a comma separated list of field, field:asc and field:desc
+	Sort   string `json:"sort,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+// listArgs may be given to any list command.
+var listArgs = []string{constants.FILTER, constants.SORT, constants.LIMIT, constants.OFFSET}
+
+type fieldFilter struct {
+	field   string
+	pattern string
+	negated bool
+}
+
+type sortKey struct {
+	field      string
+	descending bool
+}
+
+// withListArgs returns requiredArgs with the list options given in args.
+func withListArgs(args map[string]interface{}, requiredArgs []string) []string {
+	for _, arg := range listArgs {
+		if _, ok := args[arg]; ok {
+			requiredArgs = append(requiredArgs, arg)
+		}
+	}
+	return requiredArgs
+}
+
+// parseListArgs checks the list options in args against fields, the fields of
+// the items listed, and converts limit and offset to numbers.
+func parseListArgs(args map[string]interface{}, fields map[string]string) error {
+	if val, ok := args[constants.FILTER]; ok {
+		if _, err := parseFilter(fmt.Sprint(val), fields); err != nil {
+			return fmt.Errorf("invalid value of %s is provided: %v", constants.FILTER, err)
+		}
+	}
+	if val, ok := args[constants.SORT]; ok {
+		if _, err := parseSort(fmt.Sprint(val), fields); err != nil {
+			return fmt.Errorf("invalid value of %s is provided: %v", constants.SORT, err)
+		}
+	}
+	for _, arg := range []string{constants.LIMIT, constants.OFFSET} {
+		val, ok := args[arg]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(fmt.Sprint(val))
+		if err != nil || n < 0 || (arg == constants.LIMIT && n == 0) {
+			return fmt.Errorf("invalid value of %s is provided", arg)
+		}
+		args[arg] = n
+	}
+	return nil
+}
+
+// checkField checks that field is one of fields. Any field is accepted when
+// fields is nil.
+func checkField(field string, fields map[string]string) error {
+	if _, ok := fields[field]; ok || fields == nil {
+		return nil
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown field %q, fields are %s", field, strings.Join(names, ", "))
+}
+
+func parseFilter(s string, fields map[string]string) ([]fieldFilter, error) {
+	filters := []fieldFilter{}
+	if strings.TrimSpace(s) == "" {
+		return filters, nil
+	}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		var filter fieldFilter
+		if tokens := strings.SplitN(term, "!=", 2); len(tokens) == 2 {
+			filter = fieldFilter{field: tokens[0], pattern: tokens[1], negated: true}
+		} else if tokens := strings.SplitN(term, "=", 2); len(tokens) == 2 {
+			filter = fieldFilter{field: tokens[0], pattern: tokens[1]}
+		} else {
+			return nil, fmt.Errorf("filter %q is not of the form field=pattern or field!=pattern", term)
+		}
+		filter.field = strings.ToLower(strings.TrimSpace(filter.field))
+		if err := checkField(filter.field, fields); err != nil {
+			return nil, err
+		}
+		if _, err := path.Match(filter.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q of field %s", filter.pattern, filter.field)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func parseSort(s string, fields map[string]string) ([]sortKey, error) {
+	keys := []sortKey{}
+	for _, term := range strings.Split(s, ",") {
+		tokens := strings.SplitN(strings.TrimSpace(term), ":", 2)
+		key := sortKey{field: strings.ToLower(tokens[0])}
+		if len(tokens) == 2 {
+			switch strings.ToLower(tokens[1]) {
+			case "asc":
+			case "desc":
+				key.descending = true
+			default:
+				return nil, fmt.Errorf("sort order %q is neither asc nor desc", tokens[1])
+			}
+		}
+		if err := checkField(key.field, fields); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// compareFields compares two field values, as numbers when both are.
+func compareFields(a, b string) int {
+	x, errX := strconv.ParseInt(a, 10, 64)
+	y, errY := strconv.ParseInt(b, 10, 64)
+	if errX == nil && errY == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// FilterOptions returns the filters of opts on fields other than except,
+// without sorting and paging. They may be applied before the fields in except
+// are looked up, so that no lookup is made for items left out.
+func (opts ListOptions) FilterOptions(except ...string) ListOptions {
+	filters, _ := parseFilter(opts.Filter, nil)
+	terms := []string{}
+	for _, filter := range filters {
+		excepted := false
+		for _, field := range except {
+			excepted = excepted || filter.field == field
+		}
+		if excepted {
+			continue
+		}
+		operator := "="
+		if filter.negated {
+			operator = "!="
+		}
+		terms = append(terms, filter.field+operator+filter.pattern)
+	}
+	return ListOptions{Filter: strings.Join(terms, ",")}
+}
+
+// Select returns the indexes of the items listed, in the order they are
+// listed, out of n items. fields returns the fields of the i-th item. The
+// options must have been checked when the command arguments were parsed.
+func (opts ListOptions) Select(n int, fields func(i int) map[string]string) []int {
+	items := make([]map[string]string, n)
+	for i := range items {
+		items[i] = fields(i)
+	}
+	filters, _ := parseFilter(opts.Filter, nil)
+	selected := []int{}
+	for i, item := range items {
+		matches := true
+		for _, filter := range filters {
+			// the patterns were checked by parseFilter
+			ok, _ := path.Match(filter.pattern, item[filter.field])
+			if ok == filter.negated {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			selected = append(selected, i)
+		}
+	}
+	if opts.Sort != "" {
+		keys, _ := parseSort(opts.Sort, nil)
+		sort.SliceStable(selected, func(a, b int) bool {
+			for _, key := range keys {
+				c := compareFields(items[selected[a]][key.field], items[selected[b]][key.field])
+				if c != 0 {
+					return (c < 0) != key.descending
+				}
+			}
+			return false
+		})
+	}
+	if opts.Offset >= len(selected) {
+		return []int{}
+	}
+	selected = selected[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(selected) {
+		selected = selected[:opts.Limit]
+	}
+	return selected
+}
@@ -0,0 +1,89 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectVolumes(t *testing.T) {
+	volumes := []Volume{
+		{Name: "scratch-1", VolumeID: "a", Capacity: 10, State: "allocated", LocationID: "east"},
+		{Name: "scratch-2", VolumeID: "b", Capacity: 2, State: "visible", LocationID: "west"},
+		{Name: "results", VolumeID: "c", Capacity: 100, State: "allocated", LocationID: "east"},
+		{Name: "scratch-3", VolumeID: "d", Capacity: 10, State: "allocated", LocationID: "west"},
+	}
+	tests := []struct {
+		opts ListOptions
+		want []string
+	}{
+		{ListOptions{}, []string{"a", "b", "c", "d"}},
+		{ListOptions{Filter: "name=scratch-*"}, []string{"a", "b", "d"}},
+		{ListOptions{Filter: "name=scratch-*,state!=visible"}, []string{"a", "d"}},
+		{ListOptions{Filter: "STATE=allocated, location_id=east"}, []string{"a", "c"}},
+		{ListOptions{Filter: "name=none"}, []string{}},
+		// capacities are compared as numbers, ties keep the order of GLM
+		{ListOptions{Sort: "capacity"}, []string{"b", "a", "d", "c"}},
+		{ListOptions{Sort: "capacity:desc"}, []string{"c", "a", "d", "b"}},
+		{ListOptions{Sort: "location_id,name:desc"}, []string{"a", "c", "d", "b"}},
+		{ListOptions{Sort: "name", Limit: 2}, []string{"c", "a"}},
+		{ListOptions{Sort: "name", Limit: 2, Offset: 3}, []string{"d"}},
+		// an offset at or beyond the items left lists none
+		{ListOptions{Offset: 4}, []string{}},
+		{ListOptions{Offset: 10, Limit: 2}, []string{}},
+		{ListOptions{Filter: "name=scratch-*", Offset: 3}, []string{}},
+	}
+	for _, test := range tests {
+		ids := []string{}
+		for _, volume := range SelectVolumes(volumes, test.opts) {
+			ids = append(ids, volume.VolumeID)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%+v: got %v, want %v", test.opts, ids, test.want)
+		}
+	}
+}
+
+func TestParseListArgs(t *testing.T) {
+	fields := (&Volume{}).ListFields()
+	args := map[string]interface{}{"filter": "name=scratch-*", "sort": "capacity:desc", "limit": "5",
+		"offset": "10"}
+	if err := parseListArgs(args, fields); err != nil {
+		t.Fatal(err)
+	}
+	if args["limit"] != 5 || args["offset"] != 10 {
+		t.Errorf("got limit %v and offset %v", args["limit"], args["offset"])
+	}
+
+	tests := []struct {
+		args      map[string]interface{}
+		wantError string
+	}{
+		{map[string]interface{}{"filter": "size=10"}, `unknown field "size", fields are capacity, flavor_id`},
+		{map[string]interface{}{"filter": "name"}, "not of the form field=pattern"},
+		{map[string]interface{}{"filter": "name=[a"}, "invalid pattern"},
+		{map[string]interface{}{"sort": "name:up"}, "neither asc nor desc"},
+		{map[string]interface{}{"sort": ""}, `unknown field ""`},
+		{map[string]interface{}{"sort": "size:desc"}, `unknown field "size", fields are capacity, flavor_id`},
+		{map[string]interface{}{"sort": "name,size"}, `unknown field "size"`},
+		{map[string]interface{}{"limit": "0"}, "invalid value of limit is provided"},
+		{map[string]interface{}{"offset": "-1"}, "invalid value of offset is provided"},
+		{map[string]interface{}{"offset": "ten"}, "invalid value of offset is provided"},
+	}
+	for _, test := range tests {
+		err := parseListArgs(test.args, fields)
+		if err == nil || !strings.Contains(err.Error(), test.wantError) {
+			t.Errorf("%v: got %v, want error %q", test.args, err, test.wantError)
+		}
+	}
+}
+
+func TestFilterOptions(t *testing.T) {
+	opts := ListOptions{Filter: "name=scratch-*, mount_path=/mapr/*,state!=visible", Sort: "name", Limit: 1}
+	want := ListOptions{Filter: "name=scratch-*,state!=visible"}
+	if got := opts.FilterOptions("mount_path"); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,157 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"strings"
+	"time"
+)
+
+const (
+	SessionStateActive    = "active"
+	SessionStateExpired   = "expired"
+	SessionStateLoggedOut = "logged out"
+)
+
+// Session describes the GLM session cached for the user.
+type Session struct {
+	Username     string    `json:"username"`
+	Portal       string    `json:"portal"`
+	MembershipID string    `json:"membership_id"`
+	State        string    `json:"state"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// TokenClaims are the JWT claims of the session token used by the plugin.
+type TokenClaims struct {
+	Subject   string `json:"sub"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var supportedLoginArgs = []string{"format", "username", "password", "password-stdin"}
+
+var supportedSessionArgs = []string{"format", "username"}
+
+// DecodeTokenClaims decodes the payload of a JWT. The signature is not
+// verified; the claims are only used to describe the session to the user.
+func DecodeTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("session token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session token: %v", err)
+	}
+	claims := &TokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("failed to parse session token claims: %v", err)
+	}
+	return claims, nil
+}
+
+// Expiry returns the expiry time of the token, or the zero time when the
+// token carries no exp claim.
+func (claims *TokenClaims) Expiry() time.Time {
+	if claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
+
+// Identity returns the most readable user identity found in the claims.
+func (claims *TokenClaims) Identity() string {
+	if claims.Email != "" {
+		return claims.Email
+	}
+	if claims.Name != "" {
+		return claims.Name
+	}
+	return claims.Subject
+}
+
+// TokenExpiry returns the expiry of sessionToken from its exp claim, falling
+// back to expiresIn seconds from now for tokens that are not JWTs.
+func TokenExpiry(sessionToken string, expiresIn int64) time.Time {
+	if claims, err := DecodeTokenClaims(sessionToken); err == nil && claims.ExpiresAt != 0 {
+		return claims.Expiry()
+	}
+	if expiresIn > 0 {
+		return time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return time.Time{}
+}
+
+// NewSession describes the session of sessionToken. Tokens that are not JWTs
+// are reported as active with an unknown expiry.
+func NewSession(sessionToken string) *Session {
+	session := &Session{State: SessionStateActive}
+	claims, err := DecodeTokenClaims(sessionToken)
+	if err != nil {
+		log.Warnf("session token claims not available: %v", err)
+		return session
+	}
+	session.Username = claims.Identity()
+	session.ExpiresAt = claims.Expiry()
+	if !session.ExpiresAt.IsZero() && time.Now().After(session.ExpiresAt) {
+		session.State = SessionStateExpired
+	}
+	return session
+}
+
+// ValidateSessionArguments checks the args of the glm login, logout and
+// whoami operations.
+func ValidateSessionArguments(operationType string, args map[string]interface{}) error {
+	log.Infof("ValidateSessionArguments args %+v", redact.Map(args))
+	requiredArgs := supportedSessionArgs
+	if operationType == constants.LOGIN {
+		requiredArgs = supportedLoginArgs
+	}
+	if err := ValidateArguments(args, requiredArgs); err != nil {
+		msg := fmt.Sprintf("glm %v failed with error: %v", operationType, err)
+		log.Errorf(msg)
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (session *Session) CovertToTable(operationType string) *DisplayContent {
+	log.Infof("CovertToTable function\n")
+	display := &DisplayContent{}
+	if operationType == constants.LOGOUT {
+		display.Init(constants.LOGOUT_TABLE_COLUMNS, constants.TABLE_ROWS)
+		display.Rows[0] = make([]string, constants.LOGOUT_TABLE_COLUMNS)
+		display.Header[0] = "PORTAL"
+		display.Rows[0][0] = session.Portal
+		display.Header[1] = "STATE"
+		display.Rows[0][1] = session.State
+		return display
+	}
+	display.Init(constants.SESSION_TABLE_COLUMNS, constants.TABLE_ROWS)
+	display.Rows[0] = make([]string, constants.SESSION_TABLE_COLUMNS)
+	display.Header[0] = "USERNAME"
+	display.Rows[0][0] = session.Username
+	display.Header[1] = "PORTAL"
+	display.Rows[0][1] = session.Portal
+	display.Header[2] = "MEMBERSHIP_ID"
+	display.Rows[0][2] = session.MembershipID
+	display.Header[3] = "STATE"
+	display.Rows[0][3] = session.State
+	display.Header[4] = "EXPIRES_AT"
+	if session.ExpiresAt.IsZero() {
+		display.Rows[0][4] = "unknown"
+	} else {
+		display.Rows[0][4] = session.ExpiresAt.Local().Format(time.RFC1123)
+	}
+	return display
+}
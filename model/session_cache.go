@@ -0,0 +1,201 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SessionKey identifies a cached GLM session. A user may hold sessions for
+// several portals, accounts and memberships at the same time.
+type SessionKey struct {
+	Portal       string
+	Username     string
+	MembershipID string
+}
+
+// CachedSession is a GLM session stored in the per-user session cache.
+type CachedSession struct {
+	Portal       string    `json:"portal"`
+	Username     string    `json:"username"`
+	MembershipID string    `json:"membership_id"`
+	SessionToken string    `json:"session_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	AuthURL      string    `json:"auth_url,omitempty"`
+	AuthClientID string    `json:"auth_client_id,omitempty"`
+}
+
+// ExpiresWithin reports whether the session expires within d. Sessions with
+// an unknown expiry never do.
+func (session *CachedSession) ExpiresWithin(d time.Duration) bool {
+	if session.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(d).After(session.ExpiresAt)
+}
+
+type sessionCacheContent struct {
+	Sessions []CachedSession `json:"sessions"`
+}
+
+// SessionCache stores the GLM session tokens of the current user in a file only
+// the user can read. The system wide plugin.conf only holds configuration.
+type SessionCache struct {
+	Path string
+}
+
+// SessionCacheFile is the per-user session cache. Tests point it at a
+// temporary file.
+var SessionCacheFile = defaultSessionCacheFile()
+
+func defaultSessionCacheFile() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, constants.USER_SESSION_CACHE_FILE)
+}
+
+func NewSessionCache() *SessionCache {
+	return &SessionCache{Path: SessionCacheFile}
+}
+
+func (key SessionKey) matches(session CachedSession) bool {
+	return session.Portal == key.Portal && session.MembershipID == key.MembershipID &&
+		(key.Username == "" || session.Username == key.Username)
+}
+
+func (cache *SessionCache) load() (*sessionCacheContent, error) {
+	content := &sessionCacheContent{}
+	data, err := ioutil.ReadFile(cache.Path)
+	if os.IsNotExist(err) {
+		return content, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read session cache '%s': %v", cache.Path, err)
+	}
+	if len(data) == 0 {
+		return content, nil
+	}
+	if err := json.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("failed to parse session cache '%s': %v", cache.Path, err)
+	}
+	return content, nil
+}
+
+func (cache *SessionCache) save(content *sessionCacheContent) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session cache: %v", err)
+	}
+	if err := writeFileAtomic(cache.Path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save session cache '%s': %v", cache.Path, err)
+	}
+	return nil
+}
+
+// lock serialises access to the cache between the commands a user runs at
+// the same time.
+func (cache *SessionCache) lock(exclusive bool) (func(), error) {
+	if cache.Path == "" {
+		return nil, fmt.Errorf("session cache location is not known, HOME is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(cache.Path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create session cache directory: %v", err)
+	}
+	return lockFile(cache.Path+".lock", exclusive)
+}
+
+// update applies fn to the cache content under an exclusive lock and saves
+// the result when fn reports a change.
+func (cache *SessionCache) update(fn func(content *sessionCacheContent) bool) error {
+	unlock, err := cache.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	content, err := cache.load()
+	if err != nil {
+		return err
+	}
+	if !fn(content) {
+		return nil
+	}
+	return cache.save(content)
+}
+
+// Get returns the session cached for key. When key has no username the only
+// session cached for the portal and membership is returned. TokenError is
+// returned when there is no such session.
+func (cache *SessionCache) Get(key SessionKey) (*CachedSession, error) {
+	unlock, err := cache.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	content, err := cache.load()
+	if err != nil {
+		return nil, err
+	}
+	var found *CachedSession
+	for i, session := range content.Sessions {
+		if !key.matches(session) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several GLM sessions are cached for %s, pass the '%s' argument",
+				key.Portal, constants.USERNAME)
+		}
+		found = &content.Sessions[i]
+	}
+	if found == nil {
+		return nil, TokenError
+	}
+	return found, nil
+}
+
+// Put stores session, replacing the session cached for the same key.
+func (cache *SessionCache) Put(session CachedSession) error {
+	key := SessionKey{Portal: session.Portal, Username: session.Username, MembershipID: session.MembershipID}
+	return cache.update(func(content *sessionCacheContent) bool {
+		sessions := []CachedSession{session}
+		for _, cached := range content.Sessions {
+			if !key.matches(cached) {
+				sessions = append(sessions, cached)
+			}
+		}
+		content.Sessions = sessions
+		return true
+	})
+}
+
+// Delete removes the sessions matching key and returns how many were removed.
+// A key without username removes every session of the portal and membership.
+func (cache *SessionCache) Delete(key SessionKey) (int, error) {
+	removed := 0
+	err := cache.update(func(content *sessionCacheContent) bool {
+		sessions := []CachedSession{}
+		for _, cached := range content.Sessions {
+			if !key.matches(cached) {
+				sessions = append(sessions, cached)
+			}
+		}
+		removed = len(content.Sessions) - len(sessions)
+		content.Sessions = sessions
+		return removed > 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		log.Infof("removed %d cached GLM session(s) for %s", removed, key.Portal)
+	}
+	return removed, nil
+}
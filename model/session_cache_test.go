@@ -0,0 +1,57 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSessionCacheConcurrentUpdates(t *testing.T) {
+	const (
+		writers = 16
+		updates = 25
+	)
+	cache := &SessionCache{Path: filepath.Join(t.TempDir(), "sessions.json")}
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for u := 0; u < updates; u++ {
+				key := SessionKey{Portal: "https://glm", Username: fmt.Sprintf("user%02d-%02d", w, u), MembershipID: "m"}
+				err := cache.Put(CachedSession{Portal: key.Portal, Username: key.Username,
+					MembershipID: key.MembershipID, SessionToken: "token"})
+				if err == nil {
+					_, err = cache.Get(key)
+				}
+				if err != nil {
+					errs <- fmt.Errorf("writer %d update %d: %v", w, u, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	data, err := ioutil.ReadFile(cache.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := &sessionCacheContent{}
+	if err := json.Unmarshal(data, content); err != nil {
+		t.Fatalf("session cache is not valid JSON: %v", err)
+	}
+	if len(content.Sessions) != writers*updates {
+		t.Errorf("session cache holds %d sessions, want %d", len(content.Sessions), writers*updates)
+	}
+}
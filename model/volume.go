@@ -8,8 +8,10 @@ import (
 	"fmt"
 	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
 	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -17,6 +19,7 @@ const (
 	operationVolumeGet    = "get"
 	operationVolumeList   = "list"
 	operationVolumeDelete = "delete"
+	operationVolumeUpdate = "update"
 )
 
 type Volume struct {
@@ -33,16 +36,44 @@ type Volume struct {
 	State      glmClient.VolumeState  `json:"State,omitempty"`
 	Status     glmClient.VolumeStatus `json:"Status,omitempty"`
 	MountPath  string                 `json:"mount_path,omitempty"`
+	// PreviousCapacity is the capacity of an updated volume before the update
+	PreviousCapacity int64 `json:"previous_capacity,omitempty"`
+	// FromVolumeID is the volume a created volume takes its flavor, location,
+	// capacity and labels from
+	FromVolumeID string `json:"from_volume_id,omitempty"`
+	// Labels is nil unless labels were given or read
+	Labels Labels `json:"labels,omitempty"`
+	// Selector selects the volumes listed by their labels, see ParseSelector
+	Selector string `json:"selector,omitempty"`
+	ListOptions
 }
 
 var supportedCreateVolArgs = []string{"name", "capacity", "location_id", "description", "flavor_name",
-	"format", "username", "password"}
+	"format", "username", "password", "password-stdin"}
 
-var supportedDeleteVolArgs = []string{"volume_id", "format", "username", "password"}
+var supportedDeleteVolArgs = []string{"volume_id", "format", "username", "password", "password-stdin"}
 
-var supportedGetVolArgs = []string{"volume_id", "format", "username", "password"}
+var supportedGetVolArgs = []string{"volume_id", "format", "username", "password", "password-stdin"}
 
-var supportedListVolArgs = []string{"format", "username", "password"}
+var supportedListVolArgs = []string{"format", "username", "password", "password-stdin"}
+
+var supportedWaitVolArgs = []string{"volume_id", "state", "format", "username", "password", "password-stdin"}
+
+var supportedUpdateVolArgs = []string{"volume_id", "format", "username", "password", "password-stdin"}
+
+// supportedCreateFromVolArgs replace the capacity, location_id and flavor_name
+// of create when the volume is created from another volume.
+var supportedCreateFromVolArgs = []string{"name", "from_volume_id", "description", "format", "username", "password",
+	"password-stdin"}
+
+// updateVolArgs are the volume properties update changes. Each of them is
+// optional, ValidateUpdateVolumeRequest checks that one is given.
+var updateVolArgs = []string{constants.VOLUME_CAPACITY, constants.DESCRIPTION, constants.LABELS}
+
+// optionalArgs may be given to any command but are never required. The GLM
+// credentials can also come from the environment, stdin or a credentials file.
+var optionalArgs = []string{constants.FORMAT_KEY, constants.USERNAME, constants.PASSWORD, constants.PASSWORD_STDIN,
+	constants.DEBUG, constants.TIMEOUT, constants.WAIT, constants.WAIT_TIMEOUT, constants.TRACE}
 
 func ValidateArguments(args map[string]interface{}, requiredArgs []string) error {
 	for key := range args {
@@ -53,13 +84,15 @@ func ValidateArguments(args map[string]interface{}, requiredArgs []string) error
 				break
 			}
 		}
-		if !found {
+		if !found && !isOptionalArg(key) {
 			msg := fmt.Sprintf("Invalid argument %s", key)
 			return errors.New(msg)
 		}
 	}
-	requiredArgs = RemoveString(requiredArgs, constants.FORMAT_KEY)
 	for _, key := range requiredArgs {
+		if isOptionalArg(key) {
+			continue
+		}
 		if _, ok := args[key]; !ok {
 			msg := fmt.Sprintf("Argument '%s' is missing. For usage, execute 'singularity volume' command", key)
 			return errors.New(msg)
@@ -68,17 +101,44 @@ func ValidateArguments(args map[string]interface{}, requiredArgs []string) error
 	return nil
 }
 
+func isOptionalArg(key string) bool {
+	for _, value := range optionalArgs {
+		if key == value {
+			return true
+		}
+	}
+	return false
+}
+
 func NewVolume(operationType string, args map[string]interface{}) (*Volume, error) {
-	log.Infof("NewVolume args %+v", args)
+	log.Infof("NewVolume args %+v", redact.Map(args))
 	var requiredArgs []string
 	if operationType == constants.CREATE {
 		requiredArgs = supportedCreateVolArgs
+		if _, ok := args[constants.FROM_VOLUME_ID]; ok {
+			requiredArgs = supportedCreateFromVolArgs
+		}
+		if _, ok := args[constants.LABELS]; ok {
+			requiredArgs = append(requiredArgs, constants.LABELS)
+		}
 	} else if operationType == constants.DELETE {
 		requiredArgs = supportedDeleteVolArgs
 	} else if operationType == constants.GET {
 		requiredArgs = supportedGetVolArgs
 	} else if operationType == constants.LIST {
-		requiredArgs = supportedListVolArgs
+		requiredArgs = withListArgs(args, supportedListVolArgs)
+		if _, ok := args[constants.SELECTOR]; ok {
+			requiredArgs = append(requiredArgs, constants.SELECTOR)
+		}
+	} else if operationType == constants.WAIT {
+		requiredArgs = supportedWaitVolArgs
+	} else if operationType == constants.UPDATE {
+		requiredArgs = supportedUpdateVolArgs
+		for _, key := range updateVolArgs {
+			if _, ok := args[key]; ok {
+				requiredArgs = append(requiredArgs, key)
+			}
+		}
 	}
 	err := ValidateArguments(args, requiredArgs)
 	if err != nil {
@@ -87,11 +147,40 @@ func NewVolume(operationType string, args map[string]interface{}) (*Volume, erro
 		return nil, errors.New(msg)
 	}
 	volume := &Volume{}
+	if err := parseListArgs(args, volume.ListFields()); err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+	if val, ok := args[constants.STATE]; ok {
+		args[constants.STATE] = strings.ToLower(fmt.Sprint(val))
+	}
 	if val, ok := args[constants.VOLUME_CAPACITY]; ok {
 		capacity := fmt.Sprintf("%v", val)
 		capacityInt64, _ := strconv.ParseInt(capacity, constants.BASE, constants.BIT_SIZE)
+		// a capacity of 0 leaves the capacity of an updated volume unchanged
+		if capacityInt64 <= 0 && operationType == constants.UPDATE {
+			msg := fmt.Sprintf("invalid value of %s is provided", constants.VOLUME_CAPACITY)
+			log.Errorf(msg)
+			return nil, errors.New(msg)
+		}
 		args[constants.VOLUME_CAPACITY] = capacityInt64
 	}
+	if val, ok := args[constants.LABELS]; ok {
+		labels, err := ParseLabels(fmt.Sprint(val))
+		if err != nil {
+			msg := fmt.Sprintf("invalid value of %s is provided: %v", constants.LABELS, err)
+			log.Errorf(msg)
+			return nil, errors.New(msg)
+		}
+		args[constants.LABELS] = labels
+	}
+	if val, ok := args[constants.SELECTOR]; ok {
+		if _, err := ParseSelector(fmt.Sprint(val)); err != nil {
+			msg := fmt.Sprintf("invalid value of %s is provided: %v", constants.SELECTOR, err)
+			log.Errorf(msg)
+			return nil, errors.New(msg)
+		}
+	}
 
 	jsonString, _ := json.Marshal(args)
 	// convert json to struct
@@ -132,6 +221,10 @@ func (vol *Volume) CovertToTable(operationType string) *DisplayContent {
 		display.Rows[0][4] = string(vol.Status)
 		display.Header[5] = "STATE"
 		display.Rows[0][5] = string(vol.State)
+		if operationType == operationVolumeGet {
+			display.Header = append(display.Header, "LABELS")
+			display.Rows[0] = append(display.Rows[0], vol.Labels.String())
+		}
 	} else if operationType == operationVolumeList {
 		display.Init(constants.LIST_TABLE_COLUMNS, constants.TABLE_ROWS)
 		display.Rows[0] = make([]string, constants.LIST_TABLE_COLUMNS)
@@ -141,6 +234,19 @@ func (vol *Volume) CovertToTable(operationType string) *DisplayContent {
 		display.Rows[0][1] = vol.VolumeID
 		display.Header[2] = "MOUNT_PATH"
 		display.Rows[0][2] = vol.MountPath
+	} else if operationType == operationVolumeUpdate {
+		display.Init(constants.UPDATE_VOL_TABLE_COLUMNS, constants.TABLE_ROWS)
+		display.Rows[0] = make([]string, constants.UPDATE_VOL_TABLE_COLUMNS)
+		display.Header[0] = "NAME"
+		display.Rows[0][0] = vol.Name
+		display.Header[1] = "ID"
+		display.Rows[0][1] = vol.VolumeID
+		display.Header[2] = "PREVIOUS_CAPACITY"
+		display.Rows[0][2] = strconv.FormatInt(vol.PreviousCapacity, 10)
+		display.Header[3] = "CAPACITY"
+		display.Rows[0][3] = strconv.FormatInt(vol.Capacity, 10)
+		display.Header[4] = "STATE"
+		display.Rows[0][4] = string(vol.State)
 	} else if operationType == operationVolumeDelete {
 		display.Init(constants.DELETE_TABLE_COLUMNS, constants.TABLE_ROWS)
 		display.Rows[0] = make([]string, constants.DELETE_TABLE_COLUMNS)
@@ -152,11 +258,35 @@ func (vol *Volume) CovertToTable(operationType string) *DisplayContent {
 	return display
 }
 
+// ListFields returns the fields list options filter and sort volumes by.
+func (vol *Volume) ListFields() map[string]string {
+	return map[string]string{
+		"name":        vol.Name,
+		"id":          vol.VolumeID,
+		"flavor_id":   vol.FlavorID,
+		"capacity":    strconv.FormatInt(vol.Capacity, 10),
+		"location_id": vol.LocationID,
+		"status":      string(vol.Status),
+		"state":       string(vol.State),
+		"mount_path":  vol.MountPath,
+	}
+}
+
+// SelectVolumes returns the volumes listed with opts.
+func SelectVolumes(volumes []Volume, opts ListOptions) []Volume {
+	selected := []Volume{}
+	for _, i := range opts.Select(len(volumes), func(i int) map[string]string { return volumes[i].ListFields() }) {
+		selected = append(selected, volumes[i])
+	}
+	return selected
+}
+
 func CreateResponse(resp glmClient.Volume, operationType string) *Volume {
 	log.Infof("CreateResponse %+v\n", resp)
 	vol := &Volume{}
-	if operationType == "create" || operationType == "get" {
+	if operationType == "create" || operationType == "get" || operationType == "update" {
 		vol.Name = resp.Name
+		vol.Description, vol.Labels = DecodeDescription(resp.Description)
 		vol.VolumeID = resp.ID
 		vol.FlavorID = resp.FlavorID
 		vol.Capacity = resp.Capacity
@@ -167,7 +297,11 @@ func CreateResponse(resp glmClient.Volume, operationType string) *Volume {
 		vol.Name = resp.Name
 		vol.VolumeID = resp.ID
 		vol.FlavorID = resp.FlavorID
+		vol.Capacity = resp.Capacity
+		vol.LocationID = resp.LocationID
 		vol.State = resp.State
+		vol.Status = resp.Status
+		vol.Description, vol.Labels = DecodeDescription(resp.Description)
 	} else if operationType == "delete" {
 		vol.VolumeID = resp.ID
 		vol.State = resp.State
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	glmClient "github.com/hewlettpackard/hpegl-metal-client/v1/pkg/client"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -32,15 +33,18 @@ type VolumeAttachment struct {
 	AttachmentID string                `json:"attachment_id"`
 	State        glmClient.VaStateEnum `json:"State,omitempty"`
 	FSConfig     *glmClient.VafsConfig `json:"FSConfig,omitempty"`
+	// Selector selects the attachments listed by the labels of their volume
+	Selector string `json:"selector,omitempty"`
+	ListOptions
 }
 
-var supportedCreateAttachmentArgs = []string{"name", "volume_id", "format", "username", "password"}
+var supportedCreateAttachmentArgs = []string{"name", "volume_id", "format", "username", "password", "password-stdin"}
 
-var supportedDeleteAttachmentArgs = []string{"attachment_id", "format", "username", "password"}
+var supportedDeleteAttachmentArgs = []string{"attachment_id", "format", "username", "password", "password-stdin"}
 
-var supportedGetAttachmentArgs = []string{"attachment_id", "format", "username", "password"}
+var supportedGetAttachmentArgs = []string{"attachment_id", "format", "username", "password", "password-stdin"}
 
-var supportedListAttachmentArgs = []string{"format", "username", "password"}
+var supportedListAttachmentArgs = []string{"format", "username", "password", "password-stdin"}
 
 func MakeVolumeAttachment(operationType string, args map[string]interface{}) (*VolumeAttachment, error) {
 	var requiredArgs []string
@@ -51,7 +55,10 @@ func MakeVolumeAttachment(operationType string, args map[string]interface{}) (*V
 	} else if operationType == constants.GET {
 		requiredArgs = supportedGetAttachmentArgs
 	} else if operationType == constants.LIST {
-		requiredArgs = supportedListAttachmentArgs
+		requiredArgs = withListArgs(args, supportedListAttachmentArgs)
+		if _, ok := args[constants.SELECTOR]; ok {
+			requiredArgs = append(requiredArgs, constants.SELECTOR)
+		}
 	}
 	err := ValidateArguments(args, requiredArgs)
 	if err != nil {
@@ -59,7 +66,18 @@ func MakeVolumeAttachment(operationType string, args map[string]interface{}) (*V
 		log.Errorf(msg)
 		return nil, errors.New(msg)
 	}
+	if val, ok := args[constants.SELECTOR]; ok {
+		if _, err := ParseSelector(fmt.Sprint(val)); err != nil {
+			msg := fmt.Sprintf("invalid value of %s is provided: %v", constants.SELECTOR, err)
+			log.Errorf(msg)
+			return nil, errors.New(msg)
+		}
+	}
 	volumeAttachment := &VolumeAttachment{}
+	if err := parseListArgs(args, volumeAttachment.ListFields()); err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
 	jsonString, _ := json.Marshal(args)
 	// convert json to struct
 	err = json.Unmarshal(jsonString, volumeAttachment)
@@ -142,8 +160,29 @@ func (attachment *VolumeAttachment) CovertToTable(operationType string) *Display
 	return display
 }
 
+// ListFields returns the fields list options filter and sort attachments by.
+func (attachment *VolumeAttachment) ListFields() map[string]string {
+	return map[string]string{
+		"name":      attachment.Name,
+		"id":        attachment.AttachmentID,
+		"volume_id": attachment.VolumeID,
+		"state":     string(attachment.State),
+	}
+}
+
+// SelectVolumeAttachments returns the attachments listed with opts.
+func SelectVolumeAttachments(attachments []VolumeAttachment, opts ListOptions) []VolumeAttachment {
+	selected := []VolumeAttachment{}
+	for _, i := range opts.Select(len(attachments), func(i int) map[string]string {
+		return attachments[i].ListFields()
+	}) {
+		selected = append(selected, attachments[i])
+	}
+	return selected
+}
+
 func CreateVolumeAttachmentResponse(resp glmClient.VolumeAttachment, operationType string) *VolumeAttachment {
-	log.Infof("Response %+v\n", resp)
+	log.Infof("Response %+v\n", redact.Value(resp))
 	volAttachment := &VolumeAttachment{}
 	if operationType == "create" || operationType == "get" {
 		volAttachment.Name = resp.Name
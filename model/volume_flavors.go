@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 )
 
@@ -19,15 +20,16 @@ type VolumeFlavor struct {
 	ID string `json:"ID,omitempty"`
 	// Typical user-visible name for a volume flavor
 	Name string `json:"Name,omitempty"`
+	ListOptions
 }
 
-var supportedListFlavorArgs = []string{"format", "username", "password"}
+var supportedListFlavorArgs = []string{"format", "username", "password", "password-stdin"}
 
 func MakeVolumeFlavor(operationType string, args map[string]interface{}) (*VolumeFlavor, error) {
-	log.Infof("MakeVolumeFlavor args %+v", args)
+	log.Infof("MakeVolumeFlavor args %+v", redact.Map(args))
 	var requiredArgs []string
 	if operationType == constants.LIST {
-		requiredArgs = supportedListFlavorArgs
+		requiredArgs = withListArgs(args, supportedListFlavorArgs)
 	}
 	err := ValidateArguments(args, requiredArgs)
 	if err != nil {
@@ -36,6 +38,10 @@ func MakeVolumeFlavor(operationType string, args map[string]interface{}) (*Volum
 		return nil, errors.New(msg)
 	}
 	volumeFlavor := &VolumeFlavor{}
+	if err := parseListArgs(args, volumeFlavor.ListFields()); err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
 	jsonString, err := json.Marshal(args)
 	if err != nil {
 		msg := fmt.Sprintf("Volume flavor marshalling failed with error: %+v", err)
@@ -64,3 +70,20 @@ func (volFlavor *VolumeFlavor) CovertToTable(operationType string) *DisplayConte
 	}
 	return display
 }
+
+// ListFields returns the fields list options filter and sort flavors by.
+func (volFlavor *VolumeFlavor) ListFields() map[string]string {
+	return map[string]string{
+		"name": volFlavor.Name,
+		"id":   volFlavor.ID,
+	}
+}
+
+// SelectVolumeFlavors returns the flavors listed with opts.
+func SelectVolumeFlavors(flavors []VolumeFlavor, opts ListOptions) []VolumeFlavor {
+	selected := []VolumeFlavor{}
+	for _, i := range opts.Select(len(flavors), func(i int) map[string]string { return flavors[i].ListFields() }) {
+		selected = append(selected, flavors[i])
+	}
+	return selected
+}
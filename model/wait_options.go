@@ -0,0 +1,20 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package model
+
+import "time"
+
+// WaitOptions control how commands wait for asynchronous GLM operations such
+// as volume creation to complete.
+type WaitOptions struct {
+	// Wait is false when the command returns as soon as GLM accepted the
+	// request, reporting the in-progress state.
+	Wait bool
+	// Timeout bounds the wait. 0 selects the default wait timeout.
+	Timeout time.Duration
+}
+
+// DefaultWaitOptions waits with the default wait timeout.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{Wait: true}
+}
@@ -0,0 +1,110 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+// Package redact masks secrets (passwords, bearer tokens, MapR tickets) before
+// values are written to the plugin log file.
+package redact
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const Mask = "********"
+
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"ticket",
+	"authorization",
+}
+
+var (
+	// Authorization: Bearer <token> and Authorization: MAPR-Negotiate <ticket>
+	authSchemeRegex = regexp.MustCompile(`(?i)\b(bearer|mapr-negotiate)(\s+)[^\s"\]\}]+`)
+	// "password":"value" in JSON bodies
+	jsonValueRegex = regexp.MustCompile(`(?i)("[\w-]*(?:` + strings.Join(sensitiveKeys, "|") + `)[\w-]*"\s*:\s*)"[^"]*"`)
+	// password=value in command line args and password:value in %+v dumps
+	keyValueRegex = regexp.MustCompile(`(?i)\b([\w-]*(?:` + strings.Join(sensitiveKeys, "|") + `)[\w-]*)([=:])[^\s,&\]\}]+`)
+	// JWTs and long base64 blobs such as the signature part of a MapR ticket
+	blobRegex = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]*|[A-Za-z0-9+/]{40,}={0,2}`)
+)
+
+// IsSensitiveKey reports whether values stored under key must not be logged.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks every secret it can recognise in s.
+func String(s string) string {
+	s = authSchemeRegex.ReplaceAllString(s, "${1}${2}"+Mask)
+	s = jsonValueRegex.ReplaceAllString(s, `${1}"`+Mask+`"`)
+	s = keyValueRegex.ReplaceAllString(s, "${1}${2}"+Mask)
+	return blobRegex.ReplaceAllString(s, Mask)
+}
+
+// Value formats v with %+v and masks the result.
+func Value(v interface{}) string {
+	return String(fmt.Sprintf("%+v", v))
+}
+
+// Args returns a copy of the key=value command line args with sensitive values
+// masked.
+func Args(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		tokens := strings.SplitN(arg, "=", 2)
+		if len(tokens) == 2 && IsSensitiveKey(tokens[0]) {
+			redacted[i] = tokens[0] + "=" + Mask
+		} else {
+			redacted[i] = String(arg)
+		}
+	}
+	return redacted
+}
+
+// Map returns a copy of the parsed command line args with sensitive values
+// masked.
+func Map(args map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if IsSensitiveKey(key) {
+			redacted[key] = Mask
+		} else {
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+// Header returns a copy of h with credentials masked. The authorization scheme
+// is kept so that the log still shows which kind of credential was sent.
+func Header(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for key, values := range h {
+		masked := make([]string, len(values))
+		for i, value := range values {
+			if IsSensitiveKey(key) || strings.EqualFold(key, "Cookie") {
+				scheme := strings.SplitN(value, " ", 2)
+				if len(scheme) == 2 {
+					masked[i] = scheme[0] + " " + Mask
+				} else {
+					masked[i] = Mask
+				}
+			} else {
+				masked[i] = String(value)
+			}
+		}
+		redacted[key] = masked
+	}
+	return redacted
+}
@@ -0,0 +1,280 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"golang.org/x/term"
+	"gopkg.in/ini.v1"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials holds the GLM username and password resolved for a command.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) complete() bool {
+	return c.Username != "" && c.Password != ""
+}
+
+// CredentialProvider fills in whichever of the username and password are still
+// empty in creds. A provider that has nothing to offer leaves creds untouched.
+type CredentialProvider interface {
+	Retrieve(creds *Credentials, argsMap map[string]interface{}) error
+}
+
+// StdinCredentialProvider reads the password from the first line of standard
+// input when the command line carries password-stdin=true.
+type StdinCredentialProvider struct {
+	Reader io.Reader
+}
+
+// EnvCredentialProvider reads GLM_USERNAME and GLM_PASSWORD.
+type EnvCredentialProvider struct{}
+
+// FileCredentialProvider reads the [glm_credentials] section of a per-user
+// credentials file. The file must not be accessible by group or others.
+type FileCredentialProvider struct {
+	Path string
+}
+
+// ArgsCredentialProvider reads username= and password= from the command line.
+// What is given for a single command overrides the environment and the files.
+type ArgsCredentialProvider struct{}
+
+// PluginConfCredentialProvider reads the base64 encoded glmUsername and
+// glmPassword keys of the [glm_credentials] section of plugin.conf, shared by
+// all the users of the host.
+type PluginConfCredentialProvider struct{}
+
+// PromptCredentialProvider asks for the missing values without echoing the
+// password. It only prompts when standard input is a terminal.
+type PromptCredentialProvider struct {
+	In  *os.File
+	Out io.Writer
+}
+
+// CredentialChain consults its providers in order until both the username and
+// the password are known.
+type CredentialChain struct {
+	providers []CredentialProvider
+}
+
+func NewCredentialChain(providers ...CredentialProvider) *CredentialChain {
+	return &CredentialChain{providers: providers}
+}
+
+// NewDefaultCredentialChain returns the chain used by the command handlers:
+// password-stdin and the command line, environment, credentials file,
+// plugin.conf and finally an interactive prompt.
+func NewDefaultCredentialChain() *CredentialChain {
+	return NewCredentialChain(
+		&StdinCredentialProvider{Reader: os.Stdin},
+		&ArgsCredentialProvider{},
+		&EnvCredentialProvider{},
+		&FileCredentialProvider{Path: DefaultCredentialsFile()},
+		&PluginConfCredentialProvider{},
+		&PromptCredentialProvider{In: os.Stdin, Out: os.Stderr},
+	)
+}
+
+func (chain *CredentialChain) Resolve(argsMap map[string]interface{}) (string, string, error) {
+	creds := &Credentials{}
+	for _, provider := range chain.providers {
+		if creds.complete() {
+			break
+		}
+		if err := provider.Retrieve(creds, argsMap); err != nil {
+			return "", "", err
+		}
+	}
+	if creds.Username == "" {
+		return "", "", missingCredentialError(constants.USERNAME)
+	}
+	if creds.Password == "" {
+		return "", "", missingCredentialError(constants.PASSWORD)
+	}
+	return creds.Username, creds.Password, nil
+}
+
+func missingCredentialError(key string) error {
+	return fmt.Errorf("GLM %s is not provided. Set %s/%s, use a credentials file, pass %s=true "+
+		"or provide the '%s' argument", key, constants.GLM_USERNAME_ENV, constants.GLM_PASSWORD_ENV,
+		constants.PASSWORD_STDIN, key)
+}
+
+// GetCredentials resolves the GLM username and password for a command using
+// the default credential chain.
+func GetCredentials(argsMap map[string]interface{}) (string, string, error) {
+	return NewDefaultCredentialChain().Resolve(argsMap)
+}
+
+// GetUsername returns the GLM username from the command line, the
+// environment, the credentials file or plugin.conf without prompting. It
+// returns an empty string when none of them has one.
+func GetUsername(argsMap map[string]interface{}) string {
+	creds := &Credentials{}
+	providers := []CredentialProvider{
+		&ArgsCredentialProvider{},
+		&EnvCredentialProvider{},
+		&FileCredentialProvider{Path: DefaultCredentialsFile()},
+		&PluginConfCredentialProvider{},
+	}
+	for _, provider := range providers {
+		if creds.Username != "" {
+			break
+		}
+		if err := provider.Retrieve(creds, argsMap); err != nil {
+			log.Warnf("GLM username lookup: %v", err)
+		}
+	}
+	return creds.Username
+}
+
+// DefaultCredentialsFile returns GLM_CREDENTIALS_FILE when set, otherwise the
+// credentials file under the user's configuration directory.
+func DefaultCredentialsFile() string {
+	if path := os.Getenv(constants.GLM_CREDENTIALS_FILE_ENV); path != "" {
+		return path
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, constants.USER_CREDENTIALS_FILE)
+}
+
+func (p *StdinCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	val, ok := argsMap[constants.PASSWORD_STDIN]
+	if !ok || fmt.Sprint(val) != "true" || creds.Password != "" {
+		return nil
+	}
+	line, err := bufio.NewReader(p.Reader).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read password from stdin: %v", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return errors.New("password-stdin is set but no password was provided on stdin")
+	}
+	creds.Password = password
+	return nil
+}
+
+func (p *EnvCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	if creds.Username == "" {
+		creds.Username = os.Getenv(constants.GLM_USERNAME_ENV)
+	}
+	if creds.Password == "" {
+		creds.Password = os.Getenv(constants.GLM_PASSWORD_ENV)
+	}
+	return nil
+}
+
+func (p *FileCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	if p.Path == "" {
+		return nil
+	}
+	info, err := os.Stat(p.Path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read credentials file '%s': %v", p.Path, err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("credentials file '%s' must only be accessible by its owner (mode 0600)", p.Path)
+	}
+	cfg, err := ini.Load(p.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read credentials file '%s'", p.Path)
+	}
+	section := cfg.Section(constants.GLM_CREDENTIALS)
+	if creds.Username == "" {
+		creds.Username = section.Key(constants.USERNAME).String()
+	}
+	if creds.Password == "" {
+		creds.Password = section.Key(constants.PASSWORD).String()
+	}
+	log.Infof("GLM credentials read from %s", p.Path)
+	return nil
+}
+
+func (p *ArgsCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	for _, key := range []string{constants.USERNAME, constants.PASSWORD} {
+		val, ok := argsMap[key]
+		if !ok {
+			continue
+		}
+		if val == "" {
+			return fmt.Errorf("argument '%s' value is missing. For usage, execute 'singularity volume' "+
+				"command", key)
+		}
+		if key == constants.USERNAME && creds.Username == "" {
+			creds.Username = fmt.Sprint(val)
+		} else if key == constants.PASSWORD && creds.Password == "" {
+			creds.Password = fmt.Sprint(val)
+		}
+	}
+	return nil
+}
+
+func (p *PluginConfCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	glmCredDetails, err := (&model.GLMCredDetails{}).GetGLMCredDetails()
+	if err != nil {
+		// the command reports the plugin.conf error when it reads the portal
+		log.Warnf("GLM credentials lookup in plugin.conf: %v", err)
+		return nil
+	}
+	for _, key := range []string{constants.GLM_USER_NAME, constants.GLM_PASSWORD} {
+		encoded := glmCredDetails[key]
+		if encoded == "" || (key == constants.GLM_USER_NAME && creds.Username != "") ||
+			(key == constants.GLM_PASSWORD && creds.Password != "") {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("%s of plugin.conf '%s' is not base64 encoded", key, model.PluginConfFile)
+		}
+		if key == constants.GLM_USER_NAME {
+			creds.Username = string(decoded)
+		} else {
+			creds.Password = string(decoded)
+		}
+	}
+	return nil
+}
+
+func (p *PromptCredentialProvider) Retrieve(creds *Credentials, argsMap map[string]interface{}) error {
+	if p.In == nil || !term.IsTerminal(int(p.In.Fd())) {
+		return nil
+	}
+	if creds.Username == "" {
+		fmt.Fprint(p.Out, "GLM username: ")
+		line, err := bufio.NewReader(p.In).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read username: %v", err)
+		}
+		creds.Username = strings.TrimSpace(line)
+	}
+	if creds.Password == "" {
+		fmt.Fprint(p.Out, "GLM password: ")
+		password, err := term.ReadPassword(int(p.In.Fd()))
+		fmt.Fprintln(p.Out)
+		if err != nil {
+			return fmt.Errorf("failed to read password: %v", err)
+		}
+		creds.Password = string(password)
+	}
+	return nil
+}
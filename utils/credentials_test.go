@@ -0,0 +1,145 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setCredentialSources writes a credentials file and a plugin.conf with one
+// user each, and clears the environment.
+func setCredentialSources(t *testing.T) *CredentialChain {
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(credentialsFile, []byte("[glm_credentials]\nusername=file@hpe.com\n"+
+		"password=file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf := fmt.Sprintf("[glm_credentials]\nglmUsername=%s\nglmPassword=%s\n",
+		base64.StdEncoding.EncodeToString([]byte("conf@hpe.com")),
+		base64.StdEncoding.EncodeToString([]byte("conf-secret")))
+	savedConf := model.PluginConfFile
+	model.PluginConfFile = filepath.Join(dir, "plugin.conf")
+	t.Cleanup(func() { model.PluginConfFile = savedConf })
+	if err := ioutil.WriteFile(model.PluginConfFile, []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GLM_USERNAME", "")
+	t.Setenv("GLM_PASSWORD", "")
+	return NewCredentialChain(
+		&StdinCredentialProvider{Reader: strings.NewReader("stdin-secret\n")},
+		&ArgsCredentialProvider{},
+		&EnvCredentialProvider{},
+		&FileCredentialProvider{Path: credentialsFile},
+		&PluginConfCredentialProvider{},
+	)
+}
+
+func TestCredentialChainPrecedence(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]interface{}
+		env          map[string]string
+		noFile       bool
+		wantUsername string
+		wantPassword string
+	}{
+		{"args", map[string]interface{}{"username": "args@hpe.com", "password": "args-secret"},
+			map[string]string{"GLM_USERNAME": "env@hpe.com", "GLM_PASSWORD": "env-secret"}, false,
+			"args@hpe.com", "args-secret"},
+		{"password-stdin", map[string]interface{}{"username": "args@hpe.com", "password-stdin": "true"},
+			map[string]string{"GLM_PASSWORD": "env-secret"}, false, "args@hpe.com", "stdin-secret"},
+		{"env", map[string]interface{}{},
+			map[string]string{"GLM_USERNAME": "env@hpe.com", "GLM_PASSWORD": "env-secret"}, false,
+			"env@hpe.com", "env-secret"},
+		{"args and env", map[string]interface{}{"username": "args@hpe.com"},
+			map[string]string{"GLM_PASSWORD": "env-secret"}, false, "args@hpe.com", "env-secret"},
+		{"file", map[string]interface{}{}, nil, false, "file@hpe.com", "file-secret"},
+		{"env and file", map[string]interface{}{}, map[string]string{"GLM_USERNAME": "env@hpe.com"}, false,
+			"env@hpe.com", "file-secret"},
+		{"plugin.conf", map[string]interface{}{}, nil, true, "conf@hpe.com", "conf-secret"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := setCredentialSources(t)
+			for key, val := range test.env {
+				t.Setenv(key, val)
+			}
+			if test.noFile {
+				chain.providers[3] = &FileCredentialProvider{Path: filepath.Join(t.TempDir(), "missing")}
+			}
+			username, password, err := chain.Resolve(test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if username != test.wantUsername || password != test.wantPassword {
+				t.Errorf("got %s/%s, want %s/%s", username, password, test.wantUsername, test.wantPassword)
+			}
+		})
+	}
+}
+
+func TestFileCredentialProviderMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := ioutil.WriteFile(path, []byte("[glm_credentials]\nusername=file@hpe.com\npassword=secret\n"),
+		0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mode      os.FileMode
+		wantError bool
+	}{
+		{0600, false},
+		{0400, false},
+		{0640, true},
+		{0644, true},
+		{0606, true},
+	}
+	for _, test := range tests {
+		if err := os.Chmod(path, test.mode); err != nil {
+			t.Fatal(err)
+		}
+		creds := &Credentials{}
+		err := (&FileCredentialProvider{Path: path}).Retrieve(creds, map[string]interface{}{})
+		if test.wantError {
+			if err == nil || !strings.Contains(err.Error(), "mode 0600") {
+				t.Errorf("mode %o: got %v, want a mode error", test.mode, err)
+			}
+		} else if err != nil || creds.Username != "file@hpe.com" || creds.Password != "secret" {
+			t.Errorf("mode %o: got %+v, %v", test.mode, creds, err)
+		}
+	}
+}
+
+func TestStdinCredentialProvider(t *testing.T) {
+	tests := []struct {
+		input        string
+		args         map[string]interface{}
+		wantPassword string
+		wantError    bool
+	}{
+		{"secret\n", map[string]interface{}{"password-stdin": "true"}, "secret", false},
+		{"secret\r\nignored\n", map[string]interface{}{"password-stdin": "true"}, "secret", false},
+		{"secret", map[string]interface{}{"password-stdin": "true"}, "secret", false},
+		{"with spaces \n", map[string]interface{}{"password-stdin": "true"}, "with spaces ", false},
+		{"\n", map[string]interface{}{"password-stdin": "true"}, "", true},
+		{"", map[string]interface{}{"password-stdin": "true"}, "", true},
+		{"secret\n", map[string]interface{}{"password-stdin": "false"}, "", false},
+		{"secret\n", map[string]interface{}{}, "", false},
+	}
+	for _, test := range tests {
+		creds := &Credentials{}
+		err := (&StdinCredentialProvider{Reader: strings.NewReader(test.input)}).Retrieve(creds, test.args)
+		if (err != nil) != test.wantError || creds.Password != test.wantPassword {
+			t.Errorf("%q %v: got %q, %v, want %q", test.input, test.args, creds.Password, err,
+				test.wantPassword)
+		}
+	}
+}
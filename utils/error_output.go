@@ -0,0 +1,71 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hpe-hcss/lh-cdc-singularity/constants"
+	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrorOutput is the error object written to stderr when format=json is
+// selected, so that stdout only ever carries results.
+type ErrorOutput struct {
+	Code         int    `json:"code"`
+	Category     string `json:"category"`
+	Message      string `json:"message"`
+	ResourceType string `json:"resource_type"`
+	Operation    string `json:"operation,omitempty"`
+	StatusCode   int    `json:"status_code,omitempty"`
+	RequestID    string `json:"request_id,omitempty"`
+}
+
+func NewErrorOutput(err error, resourceType, operation string) *ErrorOutput {
+	output := &ErrorOutput{
+		Code:         model.ExitCode(err),
+		Category:     string(model.Category(err)),
+		Message:      err.Error(),
+		ResourceType: resourceType,
+		Operation:    operation,
+	}
+	var apiErr *model.APIError
+	if errors.As(err, &apiErr) {
+		output.StatusCode = apiErr.StatusCode
+		output.RequestID = apiErr.RequestID
+	}
+	return output
+}
+
+// GetFormat returns the format= argument of a command line, "table" when it is
+// not given. It works on the raw arguments, so that errors in the other
+// arguments are still reported in the requested format.
+func GetFormat(args []string) string {
+	format := constants.FORMAT_TABLE
+	for _, arg := range args {
+		tokens := strings.SplitN(strings.TrimSpace(arg), "=", 2)
+		if len(tokens) == 2 && tokens[constants.ARG_KEY_INDEX] == constants.FORMAT_KEY {
+			format = tokens[constants.ARG_VALUE_INDEX]
+		}
+	}
+	return format
+}
+
+// PrintError reports a failed command. With format=json the error is written
+// to stderr as an ErrorOutput object, otherwise as text on stdout.
+func PrintError(err error, format string, resourceType, operation string) {
+	if format == constants.FORMAT_JSON {
+		WriteJSONError(os.Stderr, err, resourceType, operation)
+		return
+	}
+	fmt.Printf("Error: %v\n", err)
+}
+
+func WriteJSONError(w io.Writer, err error, resourceType, operation string) {
+	data, _ := json.Marshal(NewErrorOutput(err, resourceType, operation))
+	fmt.Fprintln(w, string(data))
+}
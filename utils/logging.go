@@ -0,0 +1,154 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	"fmt"
+	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
+	log "github.com/hpe-storage/common-host-libs/logger"
+	stdlog "log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LogSettings describes where the plugin log is written and how much of it is
+// kept. The LOG_FILE, LOG_MAX_SIZE and LOG_MAX_FILES environment variables
+// still override these settings.
+type LogSettings struct {
+	File       string
+	Level      string
+	MaxSizeMiB int
+	MaxFiles   int
+}
+
+var validLogLevels = []string{"trace", "debug", "info", "warn", "error"}
+
+// NewLogSettings builds the log settings from the [logging] section of
+// plugin.conf, the LOG_LEVEL environment variable and the command line.
+// debug=true on the command line raises the level to debug whatever LOG_LEVEL
+// says.
+func NewLogSettings(loggingDetails map[string]string, argsMap map[string]interface{}) (*LogSettings, error) {
+	settings := &LogSettings{
+		File:       DefaultLogFile(),
+		Level:      log.DefaultLogLevel,
+		MaxSizeMiB: log.DefaultMaxLogSize,
+		MaxFiles:   log.DefaultMaxLogFiles,
+	}
+	if val, ok := loggingDetails[constants.LOG_FILE]; ok && val != "" {
+		settings.File = val
+	}
+	if val, ok := loggingDetails[constants.LOG_LEVEL]; ok && val != "" {
+		settings.Level = strings.ToLower(val)
+	}
+	if val, ok := loggingDetails[constants.LOG_MAX_SIZE_MIB]; ok && val != "" {
+		size, err := strconv.Atoi(val)
+		if err != nil || size <= 0 || size > log.MaxLogSizeLimit {
+			return nil, fmt.Errorf("invalid %s '%s' in plugin.conf, it must be between 1 and %d",
+				constants.LOG_MAX_SIZE_MIB, val, log.MaxLogSizeLimit)
+		}
+		settings.MaxSizeMiB = size
+	}
+	if val, ok := loggingDetails[constants.LOG_MAX_FILES]; ok && val != "" {
+		files, err := strconv.Atoi(val)
+		if err != nil || files <= 0 || files > log.MaxFilesLimit {
+			return nil, fmt.Errorf("invalid %s '%s' in plugin.conf, it must be between 1 and %d",
+				constants.LOG_MAX_FILES, val, log.MaxFilesLimit)
+		}
+		settings.MaxFiles = files
+	}
+	if !isValidLogLevel(settings.Level) {
+		return nil, fmt.Errorf("invalid %s '%s' in plugin.conf, it must be one of %s",
+			constants.LOG_LEVEL, settings.Level, strings.Join(validLogLevels, ", "))
+	}
+	if val := os.Getenv("LOG_LEVEL"); val != "" {
+		settings.Level = strings.ToLower(val)
+		if !isValidLogLevel(settings.Level) {
+			return nil, fmt.Errorf("invalid LOG_LEVEL '%s', it must be one of %s", val,
+				strings.Join(validLogLevels, ", "))
+		}
+	}
+	if val, ok := argsMap[constants.DEBUG]; ok {
+		debug, err := strconv.ParseBool(fmt.Sprint(val))
+		if err != nil {
+			return nil, fmt.Errorf("argument '%s' must be true or false", constants.DEBUG)
+		}
+		if debug && settings.Level != "trace" {
+			settings.Level = "debug"
+		}
+	}
+	return settings, nil
+}
+
+func isValidLogLevel(level string) bool {
+	for _, valid := range validLogLevels {
+		if level == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultLogFile returns the per-user log file under $XDG_STATE_HOME, falling
+// back to ~/.local/state as the XDG base directory spec does.
+func DefaultLogFile() string {
+	stateDir := os.Getenv(constants.XDG_STATE_HOME_ENV)
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(os.TempDir(), filepath.Base(constants.USER_LOG_FILE))
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateDir, constants.USER_LOG_FILE)
+}
+
+// InitLogging sets up the plugin log. Rotated files are kept next to the log
+// file. In debug mode the GLM API client dumps its requests and responses
+// through the standard library logger, which is redirected, redacted, into the
+// plugin log.
+func InitLogging(settings *LogSettings) error {
+	file := settings.File
+	if envFile := os.Getenv("LOG_FILE"); envFile != "" {
+		file = envFile
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create log directory for '%s': %v", file, err)
+	}
+	// the logger applies LOG_LEVEL over the parameters, while the level of the
+	// settings already accounts for it
+	if envLevel, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		os.Setenv("LOG_LEVEL", settings.Level)
+		defer os.Setenv("LOG_LEVEL", envLevel)
+	}
+	params := &log.LogParams{
+		Level:      settings.Level,
+		File:       file,
+		MaxSizeMiB: settings.MaxSizeMiB,
+		MaxFiles:   settings.MaxFiles,
+		Format:     log.TextFormat,
+	}
+	if err := log.InitLogging("", params, false); err != nil {
+		return err
+	}
+	if DebugEnabled() {
+		stdlog.SetFlags(0)
+		stdlog.SetOutput(debugLogWriter{})
+	}
+	return nil
+}
+
+// DebugEnabled reports whether the plugin log is at debug level or lower.
+func DebugEnabled() bool {
+	level := log.GetLevel().String()
+	return level == "debug" || level == "trace"
+}
+
+type debugLogWriter struct{}
+
+func (w debugLogWriter) Write(p []byte) (int, error) {
+	log.Debugf("%s", redact.String(strings.TrimRight(string(p), "\n")))
+	return len(p), nil
+}
@@ -0,0 +1,82 @@
+// (c) Copyright 2022 Hewlett Packard Enterprise Development LP
+
+package utils
+
+import (
+	log "github.com/hpe-storage/common-host-libs/logger"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewLogSettingsLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		confLevel string
+		envLevel  string
+		debug     string
+		want      string
+		wantError bool
+	}{
+		{"default", "", "", "", log.DefaultLogLevel, false},
+		{"plugin.conf", "warn", "", "", "warn", false},
+		{"LOG_LEVEL over plugin.conf", "warn", "error", "", "error", false},
+		{"debug over plugin.conf", "warn", "", "true", "debug", false},
+		{"debug over LOG_LEVEL", "", "error", "true", "debug", false},
+		{"debug=false", "", "error", "false", "error", false},
+		{"trace is kept", "", "trace", "true", "trace", false},
+		{"invalid LOG_LEVEL", "", "loud", "", "", true},
+		{"invalid debug", "", "", "yes", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", test.envLevel)
+			args := map[string]interface{}{}
+			if test.debug != "" {
+				args["debug"] = test.debug
+			}
+			settings, err := NewLogSettings(map[string]string{"log_level": test.confLevel}, args)
+			if test.wantError {
+				if err == nil {
+					t.Errorf("got level %s, want an error", settings.Level)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if settings.Level != test.want {
+				t.Errorf("got level %s, want %s", settings.Level, test.want)
+			}
+		})
+	}
+}
+
+func TestInitLogging(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env", "singularity.log")
+	t.Setenv("LOG_FILE", envFile)
+	t.Setenv("LOG_LEVEL", "error")
+
+	settings, err := NewLogSettings(map[string]string{"log_file": filepath.Join(dir, "conf", "singularity.log")},
+		map[string]interface{}{"debug": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InitLogging(settings); err != nil {
+		t.Fatal(err)
+	}
+	if !DebugEnabled() {
+		t.Errorf("got level %s, want debug", log.GetLevel())
+	}
+	if os.Getenv("LOG_LEVEL") != "error" {
+		t.Errorf("LOG_LEVEL changed to %s", os.Getenv("LOG_LEVEL"))
+	}
+	log.Infof("logged to the LOG_FILE")
+	if _, err := os.Stat(envFile); err != nil {
+		t.Errorf("log file of LOG_FILE: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "conf")); !os.IsNotExist(err) {
+		t.Errorf("log directory of plugin.conf was created: %v", err)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"github.com/hpe-hcss/lh-cdc-singularity/constants"
 	model "github.com/hpe-hcss/lh-cdc-singularity/model"
+	"github.com/hpe-hcss/lh-cdc-singularity/redact"
 	log "github.com/hpe-storage/common-host-libs/logger"
 	"github.com/rodaine/table"
+	"os"
 	"reflect"
 	"strings"
 )
@@ -48,7 +50,9 @@ func (f *TableOutputFormatter) PrintOutput(displaycontent *model.DisplayContent)
 		//header := reflect.ValueOf(displaycontent.Header).Interface()
 		headerList[i] = reflect.ValueOf(item).Interface()
 	}
-	tbl := table.New(headerList...)
+	// os.Stdout rather than table.DefaultWriter, which is os.Stdout as it was
+	// when the program started
+	tbl := table.New(headerList...).WithWriter(os.Stdout)
 	rowLength := len(displaycontent.Rows)
 	for i := 0; i < rowLength; i++ {
 		rowList := stringToInterface(displaycontent.Rows[i])
@@ -64,7 +68,7 @@ func (f *JSONOutputFormatter) PrintOutput(displaycontent *model.DisplayContent)
 	if displaycontent == nil {
 		return errors.New("invalid display content")
 	}
-	log.Infof("json volume info %v", displaycontent)
+	log.Infof("json volume info %v", redact.Value(displaycontent))
 	result := []map[string]interface{}{}
 	for row := 0; row < len(displaycontent.Rows); row++ {
 		mp := make(map[string]interface{})
@@ -98,12 +102,18 @@ func (v *VolumeFormatter) Format(resp interface{}) *model.DisplayContent {
 			}
 			displayContent.Rows = append(displayContent.Rows, t.Rows[0])
 		}
-	} else if v.operationType == constants.GET || v.operationType == constants.CREATE {
+	} else if v.operationType == constants.GET {
+		resource := resp.(*model.Volume)
+		displayContent = resource.CovertToTable(constants.GET)
+	} else if v.operationType == constants.CREATE || v.operationType == constants.WAIT {
 		resource := resp.(*model.Volume)
 		displayContent = resource.CovertToTable(constants.CREATE)
 	} else if v.operationType == constants.DELETE {
 		resource := resp.(*model.Volume)
 		displayContent = resource.CovertToTable(constants.DELETE)
+	} else if v.operationType == constants.UPDATE {
+		resource := resp.(*model.Volume)
+		displayContent = resource.CovertToTable(constants.UPDATE)
 	}
 	return displayContent
 }
@@ -165,6 +175,19 @@ func (v *VolumeFlavorFormatter) Format(resp interface{}) *model.DisplayContent {
 	return displayContent
 }
 
+type SessionFormatter struct {
+	operationType string
+}
+
+func NewSessionFormatter(operationType string) *SessionFormatter {
+	return &SessionFormatter{operationType: operationType}
+}
+
+func (v *SessionFormatter) Format(resp interface{}) *model.DisplayContent {
+	resource := resp.(*model.Session)
+	return resource.CovertToTable(v.operationType)
+}
+
 func (f *OutputFormatter) PrintOutput(resp interface{}, resourceType string, operationType string) error {
 	var displayContent *model.DisplayContent
 	if resourceType == constants.VOLUME {
@@ -176,6 +199,9 @@ func (f *OutputFormatter) PrintOutput(resp interface{}, resourceType string, ope
 	} else if resourceType == constants.VOLUME_FLAVORS {
 		formatter := NewVolumeFlavorFormatter(operationType)
 		displayContent = formatter.Format(resp)
+	} else if resourceType == constants.GLM {
+		formatter := NewSessionFormatter(operationType)
+		displayContent = formatter.Format(resp)
 	}
 	return f.formatter.PrintOutput(displayContent)
 }
@@ -6,29 +6,13 @@ import (
 	"errors"
 	"fmt"
 	constants "github.com/hpe-hcss/lh-cdc-singularity/constants"
+	"github.com/hpe-hcss/lh-cdc-singularity/model"
 	log "github.com/hpe-storage/common-host-libs/logger"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func GetCredentials(argsMap map[string]interface{}) (string, string, error) {
-	glmCredentialsList := [2]string{constants.PASSWORD, constants.USERNAME}
-	for _, key := range glmCredentialsList {
-		if val, ok := argsMap[key]; ok {
-			if val == "" {
-				err := fmt.Errorf("argument '%s' value is missing. For usage, execute 'singularity volume' "+
-					"command", key)
-				return "", "", err
-			}
-		} else {
-			err := fmt.Errorf("argument '%s' is missing. For usage, execute 'singularity volume' command", key)
-			return "", "", err
-		}
-	}
-	password := fmt.Sprint(argsMap[constants.PASSWORD])
-	username := fmt.Sprint(argsMap[constants.USERNAME])
-	return username, password, nil
-}
-
 func MakeCommand(args []string) (map[string]interface{}, error) {
 	argsMap := map[string]interface{}{}
 	for _, arg := range args {
@@ -58,3 +42,49 @@ func ValidateOperations(operation string, supportedOperations []string) error {
 	}
 	return nil
 }
+
+// GetTimeout returns the timeout= argument, given as a Go duration such as
+// "90s" or "5m" or as a number of seconds. It returns 0 when no timeout is set.
+func GetTimeout(argsMap map[string]interface{}) (time.Duration, error) {
+	return getDurationArg(argsMap, constants.TIMEOUT)
+}
+
+// GetWaitOptions returns the wait= and wait_timeout= arguments. Commands wait
+// for asynchronous operations to complete unless wait=false is given.
+func GetWaitOptions(argsMap map[string]interface{}) (model.WaitOptions, error) {
+	opts := model.DefaultWaitOptions()
+	if val, ok := argsMap[constants.WAIT]; ok {
+		wait, err := strconv.ParseBool(fmt.Sprint(val))
+		if err != nil {
+			return opts, fmt.Errorf("argument '%s' must be true or false", constants.WAIT)
+		}
+		opts.Wait = wait
+	}
+	timeout, err := getDurationArg(argsMap, constants.WAIT_TIMEOUT)
+	if err != nil {
+		return opts, err
+	}
+	opts.Timeout = timeout
+	return opts, nil
+}
+
+func getDurationArg(argsMap map[string]interface{}, key string) (time.Duration, error) {
+	val, ok := argsMap[key]
+	if !ok {
+		return 0, nil
+	}
+	str := fmt.Sprint(val)
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(str)
+		if convErr != nil {
+			return 0, fmt.Errorf("argument '%s' value '%s' is not a duration such as 90s or 5m",
+				key, str)
+		}
+		duration = time.Duration(seconds) * time.Second
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("argument '%s' must be positive", key)
+	}
+	return duration, nil
+}
@@ -2,7 +2,7 @@
 
 package main
 
-const volumeAttachmentUsage = `volume-attachment <create|delete|get|list> <name> <volume_id> <attachment_id> [format] <username> <password>
+const volumeAttachmentUsage = `volume-attachment <create|delete|get|list> <name> <volume_id> <attachment_id> [selector] [filter] [sort] [limit] [offset] [format] [username] [password] [password-stdin] [debug] [trace] [timeout] [wait] [wait_timeout]
 
 Options
 - create
@@ -19,11 +19,39 @@ Options
     Specifies volume_id to be attached with type string. Required for volume attachment create operation only.
 - attachment_id
     Specifies volume attachment ID with type string. Required for volume attachment <get|delete> operations only.
+- selector
+    Lists only the attachments of the volumes whose labels match, e.g. "team=genomics,env!=prod". See the
+    selector option of the volume command. Optional for volume attachment list operation only.
+- filter
+    Lists only the attachments whose fields match, e.g. "state=ready". A filter is field=pattern or
+    field!=pattern, where the pattern may use the wildcards * and ?. Fields are name, id, volume_id and state.
+    Optional for <list> operation only.
+- sort
+    Sorts the listed attachments by fields, e.g. "name:desc", in asc (default) or desc order. Fields
+    holding numbers are compared as numbers. Optional for <list> operation only.
+- limit
+    Lists at most limit attachments. All the attachments are still fetched from GLM.
+    Optional for <list> operation only.
+- offset
+    Skips the first offset attachments, after filtering and sorting. All the attachments are still fetched from GLM.
+    Optional for <list> operation only.
 - format
     specifies the format of <create|get|delete|list> response. format having two values "json" or "table". 
     if format is not mentioned in the commandline then default format value will be "table".
 - username
-	specifies GLM username
+	specifies GLM username. Not required when set by GLM_USERNAME or the credentials file
 - password
-	specifies GLM password
+	specifies GLM password. Not required when set by GLM_PASSWORD, password-stdin or the credentials file
+- password-stdin
+	if set to "true" the GLM password is read from the first line of stdin
+- debug
+	if set to "true" the plugin log is written at debug level, including the GLM API requests and responses
+- trace
+	file to which every HTTP exchange with GLM is appended as a JSON line
+- timeout
+	bounds the whole operation, e.g. "90s" or "10m" (a plain number is read as seconds). Ctrl-C and SIGTERM also stop it
+- wait
+	if set to "false" create returns as soon as GLM accepted the request, with the state in progress
+- wait_timeout
+	bounds how long create polls for the attachment to become ready, "10m" by default
 `
@@ -2,13 +2,34 @@
 
 package main
 
-const volumeFlavorUsage = `volume-flavor <list> <username> <password>
+const volumeFlavorUsage = `volume-flavor <list> [filter] [sort] [limit] [offset] [username] [password] [password-stdin] [debug] [trace] [timeout]
 
 Options
 - list
     Specifies volume flavor list operation.
+- filter
+    Lists only the flavors whose fields match, e.g. "name=Fast*". A filter is field=pattern or
+    field!=pattern, where the pattern may use the wildcards * and ?. Fields are name and id.
+    Optional for <list> operation only.
+- sort
+    Sorts the listed flavors by fields, e.g. "name", in asc (default) or desc order. Fields
+    holding numbers are compared as numbers. Optional for <list> operation only.
+- limit
+    Lists at most limit flavors. All the flavors are still fetched from GLM.
+    Optional for <list> operation only.
+- offset
+    Skips the first offset flavors, after filtering and sorting. All the flavors are still fetched from GLM.
+    Optional for <list> operation only.
 - username
-	specifies GLM username
+	specifies GLM username. Not required when set by GLM_USERNAME or the credentials file
 - password
-	specifies GLM password
+	specifies GLM password. Not required when set by GLM_PASSWORD, password-stdin or the credentials file
+- password-stdin
+	if set to "true" the GLM password is read from the first line of stdin
+- debug
+	if set to "true" the plugin log is written at debug level, including the GLM API requests and responses
+- trace
+	file to which every HTTP exchange with GLM is appended as a JSON line
+- timeout
+	bounds the whole operation, e.g. "90s" or "10m" (a plain number is read as seconds). Ctrl-C and SIGTERM also stop it
 `
@@ -2,7 +2,7 @@
 
 package main
 
-const volumeUsage = `volume <create|delete|get|list> <name> <capacity> <location_id> [description] <flavor_id> <volume_id> [format] <username> <password>
+const volumeUsage = `volume <create|delete|get|list|wait|update> <name> <capacity> <location_id> [description] <flavor_id> <volume_id> [from_volume_id] [labels] [selector] [filter] [sort] [limit] [offset] [format] [username] [password] [password-stdin] [debug] [trace] [timeout]
 
 
 Options
@@ -14,24 +14,69 @@ Options
     Specifies volume list operation.
 - get 
     Specifies volume get operation.
+- wait
+    Waits until the volume reaches the given state.
+- update
+    Grows the capacity of a volume and/or changes its description and labels.
 - name
     Specifies the name of the volume with type string. Required for <create> operation only.
 - capacity
-    Specifies volume capacity in GiB with type int. Required for <create> operation only.
+    Specifies volume capacity in GiB with type int. Required for <create> operation. Optional for <update>
+    operation, where the capacity can only grow.
 - location_id
     Specifies volume location with type string. Required for <create> operation only.
 - description
-    description specifies volume description with type string. Optional for <create> operation. Not required
+    description specifies volume description with type string. Optional for <create|update> operations. Not required
     for <get|delete|list> operations.
 - flavor_name
     Specifies storage flavor name with type string. Required for <create> operation only.
 - volume_id
-    Specifies volume ID with type string. Required for <get|delete> operations only.
+    Specifies volume ID with type string. Required for <get|delete|wait|update> operations only.
+- from_volume_id
+    Specifies the ID of the volume whose flavor, location, capacity and labels a created volume gets, in place
+    of capacity, location_id and flavor_name. The labels are copied unless labels is given. The data of the
+    volume is not copied: GLM has no API to copy a volume. Optional for <create> operation only.
+- labels
+    Specifies the labels of the volume as key=value pairs separated by commas, e.g. "team=genomics,env=dev".
+    Optional for <create|update> operations, where update replaces all the labels and "labels=" removes them.
+    Keys are letters, digits and . _ / -, values are letters, digits and . _ -.
+- selector
+    Lists only the volumes whose labels match, e.g. "team=genomics,env!=prod". A requirement is key=value,
+    key!=value, key (the label is set) or !key (the label is not set), and every requirement must hold.
+    Optional for <list> operation only.
+- state
+    Specifies the volume state to wait for: allocated, visible or deleted. Required for <wait> operation only.
+- filter
+    Lists only the volumes whose fields match, e.g. "name=scratch-*,state!=visible". A filter is field=pattern or
+    field!=pattern, where the pattern may use the wildcards * and ?. Fields are name, id, flavor_id, capacity,
+    location_id, status, state and mount_path.
+    Optional for <list> operation only.
+- sort
+    Sorts the listed volumes by fields, e.g. "capacity:desc,name", in asc (default) or desc order. Fields
+    holding numbers are compared as numbers. Optional for <list> operation only.
+- limit
+    Lists at most limit volumes. All the volumes are still fetched from GLM.
+    Optional for <list> operation only.
+- offset
+    Skips the first offset volumes, after filtering and sorting. All the volumes are still fetched from GLM.
+    Optional for <list> operation only.
 - format
     specifies the format of <create|get|delete|list> response. format having two values "json" or "table". 
     If format is not mentioned in the commandline then default format value will be "table".
 - username
-	specifies GLM username
+	specifies GLM username. Not required when set by GLM_USERNAME or the credentials file
 - password
-	specifies GLM password
+	specifies GLM password. Not required when set by GLM_PASSWORD, password-stdin or the credentials file
+- password-stdin
+	if set to "true" the GLM password is read from the first line of stdin
+- debug
+	if set to "true" the plugin log is written at debug level, including the GLM API requests and responses
+- trace
+	file to which every HTTP exchange with GLM is appended as a JSON line
+- timeout
+	bounds the whole operation, e.g. "90s" or "10m" (a plain number is read as seconds). Ctrl-C and SIGTERM also stop it
+- wait
+	if set to "false" create, update and delete return as soon as GLM accepted the request, with the state in progress
+- wait_timeout
+	bounds how long create, update, delete and wait poll for the final state, "10m" by default
 `